@@ -1,24 +1,63 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os" // Use os for ReadFile
+	"path/filepath"
 
+	"github.com/adamerikoff/oq/internal/oq_diagnostic"
+	"github.com/adamerikoff/oq/internal/oq_dialect"
 	"github.com/adamerikoff/oq/internal/oq_evaluator"
 	"github.com/adamerikoff/oq/internal/oq_lexer"
 	"github.com/adamerikoff/oq/internal/oq_parser"
 	"github.com/adamerikoff/oq/internal/oq_repl"
+	"github.com/adamerikoff/oq/internal/oq_token"
+	"github.com/adamerikoff/oq/internal/oq_translator"
 )
 
 const version = "0.1"
 
 func main() {
+	// Errors are worded in the dialect active where they happened unless -lang is given.
+	lang := flag.String("lang", "", "dialect to report errors in (eng, qzq, qzl, trk, rus, uzb, kgz)")
+	scopedBuiltins := flag.Bool("scoped-builtins", false, "only accept builtin names of the active dialect")
+	dialects := flag.String("dialects", "", "extra directories with dialect files, searched before "+oq_dialect.SEARCH_PATH_ENV)
+	numbers := flag.String("numbers", "code", "how results show numbers: code (1234.5) or local (1 234,5 in the active dialect)")
+	flag.Parse()
+
+	if *numbers != "code" && *numbers != "local" {
+		fmt.Fprintf(os.Stderr, "Error: -numbers must be code or local, got %q\n", *numbers)
+		os.Exit(2)
+	}
+
+	searchPath := append(filepath.SplitList(*dialects), oq_dialect.SearchPath()...)
+	if _, err := oq_dialect.Load(searchPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading dialects: %v\n", err)
+		os.Exit(1)
+	}
+
+	// -lang may name a dialect from a file, so it is checked once they are loaded.
+	if _, ok := oq_token.AllDialectsMap[*lang]; *lang != "" && !ok {
+		fmt.Fprintf(os.Stderr, "Error: -lang must be a known dialect, got %q\n", *lang)
+		os.Exit(2)
+	}
+
+	// `oq translate` prints only the translated source, so it runs before the banner.
+	if flag.Arg(0) == "translate" {
+		if err := translateFile(flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error translating: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	fmt.Printf("oQ (go interpreter) v%s\n", version)
 
-	if len(os.Args) > 1 {
+	if flag.NArg() > 0 {
 		// A file path is provided as a command-line argument
-		filePath := os.Args[1]
-		err := runFile(filePath)
+		filePath := flag.Arg(0)
+		err := runFile(filePath, options{lang: *lang, scopedBuiltins: *scopedBuiltins, localNumbers: *numbers == "local"})
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error running file %s: %v\n", filePath, err)
 			os.Exit(1)
@@ -26,12 +65,49 @@ func main() {
 	} else {
 		// No file path, start the REPL
 		fmt.Println("Entering REPL mode. Press Ctrl+D to exit.")
-		oq_repl.Start(os.Stdin, os.Stdout)
+		oq_repl.StartWithOptions(os.Stdin, os.Stdout, oq_repl.Options{
+			LocalNumbers:   *numbers == "local",
+			Lang:           *lang,
+			ScopedBuiltins: *scopedBuiltins,
+		})
 	}
 }
 
+// translateFile implements `oq translate --to <dialect> file.oq`, writing the
+// file rewritten into the target dialect to stdout.
+func translateFile(args []string) error {
+	flags := flag.NewFlagSet("translate", flag.ContinueOnError)
+	target := flags.String("to", "", "dialect to translate into")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *target == "" || flags.NArg() != 1 {
+		return fmt.Errorf("usage: oq translate --to <dialect> file.oq")
+	}
+
+	content, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("could not read file: %w", err)
+	}
+
+	translated, err := oq_translator.Translate(string(content), *target)
+	if err != nil {
+		return fmt.Errorf("%s: %w", flags.Arg(0), err)
+	}
+
+	fmt.Print(translated)
+	return nil
+}
+
+// options holds the command-line settings that affect how a file is run.
+type options struct {
+	lang           string // dialect to report errors in; empty keeps the dialect of the error site
+	scopedBuiltins bool
+	localNumbers   bool // show the result's numbers the way the dialect active at the end of the file writes them
+}
+
 // runFile reads the content of a file and evaluates it.
-func runFile(filePath string) error {
+func runFile(filePath string, opts options) error {
 	// Use os.ReadFile instead of ioutil.ReadFile
 	content, err := os.ReadFile(filePath)
 	if err != nil {
@@ -46,17 +122,33 @@ func runFile(filePath string) error {
 
 	if len(p.Errors()) != 0 {
 		fmt.Fprintln(os.Stderr, "Parser errors:")
-		for _, msg := range p.Errors() {
-			fmt.Fprintf(os.Stderr, "\t%s\n", msg)
+		for _, diagnostic := range p.Errors() {
+			fmt.Fprint(os.Stderr, localize(diagnostic, opts.lang).Render(sourceCode))
 		}
 		return fmt.Errorf("parsing failed with %d errors", len(p.Errors()))
 	}
 
 	env := oq_evaluator.NewEnvironment() // Initialize a new environment for the file
+	env.ScopeBuiltins(opts.scopedBuiltins)
 	evaluated := oq_evaluator.Eval(program, env)
 
-	if evaluated != nil {
+	if errObj, ok := evaluated.(*oq_evaluator.Error); ok {
+		fmt.Fprint(os.Stderr, localize(errObj.Diagnostic(), opts.lang).Render(sourceCode))
+		return fmt.Errorf("evaluation failed")
+	}
+
+	if evaluated != nil && opts.localNumbers {
+		fmt.Println(oq_evaluator.Display(evaluated, l.Dialect()))
+	} else if evaluated != nil {
 		fmt.Println(evaluated.Inspect()) // Print the result of the last expression
 	}
 	return nil
 }
+
+// localize rewords diagnostic in lang, or leaves it as is when lang is empty.
+func localize(diagnostic oq_diagnostic.Diagnostic, lang string) oq_diagnostic.Diagnostic {
+	if lang == "" {
+		return diagnostic
+	}
+	return diagnostic.Localize(lang)
+}
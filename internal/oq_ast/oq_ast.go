@@ -2,6 +2,7 @@ package oq_ast
 
 import (
 	"bytes"
+	"math/big"
 	"strings"
 
 	"github.com/adamerikoff/oq/internal/oq_token"
@@ -10,6 +11,9 @@ import (
 type Node interface {
 	TokenLiteral() string
 	String() string
+	Pos() oq_token.Position    // where the node starts in the source
+	EndPos() oq_token.Position // just past the node's last character
+	Dialect() string           // dialect that was active where the node starts
 }
 
 type Expression interface {
@@ -33,6 +37,26 @@ func (p *Program) TokenLiteral() string {
 		return ""
 	}
 }
+func (p *Program) Pos() oq_token.Position {
+	if len(p.Statements) > 0 {
+		return p.Statements[0].Pos()
+	}
+	return oq_token.Position{}
+}
+
+func (p *Program) EndPos() oq_token.Position {
+	if len(p.Statements) > 0 {
+		return p.Statements[len(p.Statements)-1].EndPos()
+	}
+	return oq_token.Position{}
+}
+
+func (p *Program) Dialect() string {
+	if len(p.Statements) > 0 {
+		return p.Statements[0].Dialect()
+	}
+	return ""
+}
 func (p *Program) String() string {
 	var out bytes.Buffer
 	for _, s := range p.Statements {
@@ -46,9 +70,12 @@ type Identifier struct {
 	Value string
 }
 
-func (i *Identifier) expressionNode()      {}
-func (i *Identifier) TokenLiteral() string { return i.Token.Literal }
-func (i *Identifier) String() string       { return i.Value }
+func (i *Identifier) expressionNode()           {}
+func (i *Identifier) TokenLiteral() string      { return i.Token.Literal }
+func (i *Identifier) Pos() oq_token.Position    { return i.Token.Position }
+func (i *Identifier) EndPos() oq_token.Position { return i.Token.End }
+func (i *Identifier) Dialect() string           { return i.Token.Dialect }
+func (i *Identifier) String() string            { return i.Value }
 
 type LetStatement struct {
 	Token oq_token.Token
@@ -56,8 +83,16 @@ type LetStatement struct {
 	Value Expression
 }
 
-func (ls *LetStatement) statementNode()       {}
-func (ls *LetStatement) TokenLiteral() string { return ls.Token.Literal }
+func (ls *LetStatement) statementNode()         {}
+func (ls *LetStatement) TokenLiteral() string   { return ls.Token.Literal }
+func (ls *LetStatement) Pos() oq_token.Position { return ls.Token.Position }
+func (ls *LetStatement) Dialect() string        { return ls.Token.Dialect }
+func (ls *LetStatement) EndPos() oq_token.Position {
+	if ls.Value != nil {
+		return ls.Value.EndPos()
+	}
+	return ls.Name.EndPos()
+}
 func (ls *LetStatement) String() string {
 	var out bytes.Buffer
 	out.WriteString(ls.TokenLiteral() + " ")
@@ -69,13 +104,47 @@ func (ls *LetStatement) String() string {
 	return out.String()
 }
 
+type AssignExpression struct {
+	Token oq_token.Token // the '=' token
+	Name  *Identifier
+	Value Expression
+}
+
+func (ae *AssignExpression) expressionNode()        {}
+func (ae *AssignExpression) TokenLiteral() string   { return ae.Token.Literal }
+func (ae *AssignExpression) Pos() oq_token.Position { return ae.Token.Position }
+func (ae *AssignExpression) Dialect() string        { return ae.Token.Dialect }
+func (ae *AssignExpression) EndPos() oq_token.Position {
+	if ae.Value != nil {
+		return ae.Value.EndPos()
+	}
+	return ae.Name.EndPos()
+}
+func (ae *AssignExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString(ae.Name.String())
+	out.WriteString(" = ")
+	if ae.Value != nil {
+		out.WriteString(ae.Value.String())
+	}
+	return out.String()
+}
+
 type ReturnStatement struct {
 	Token       oq_token.Token
 	ReturnValue Expression
 }
 
-func (rs *ReturnStatement) statementNode()       {}
-func (rs *ReturnStatement) TokenLiteral() string { return rs.Token.Literal }
+func (rs *ReturnStatement) statementNode()         {}
+func (rs *ReturnStatement) TokenLiteral() string   { return rs.Token.Literal }
+func (rs *ReturnStatement) Pos() oq_token.Position { return rs.Token.Position }
+func (rs *ReturnStatement) Dialect() string        { return rs.Token.Dialect }
+func (rs *ReturnStatement) EndPos() oq_token.Position {
+	if rs.ReturnValue != nil {
+		return rs.ReturnValue.EndPos()
+	}
+	return rs.Token.End
+}
 func (rs *ReturnStatement) String() string {
 	var out bytes.Buffer
 	out.WriteString(rs.TokenLiteral() + " ")
@@ -90,8 +159,16 @@ type ExpressionStatement struct {
 	Expression Expression
 }
 
-func (es *ExpressionStatement) statementNode()       {}
-func (es *ExpressionStatement) TokenLiteral() string { return es.Token.Literal }
+func (es *ExpressionStatement) statementNode()         {}
+func (es *ExpressionStatement) TokenLiteral() string   { return es.Token.Literal }
+func (es *ExpressionStatement) Pos() oq_token.Position { return es.Token.Position }
+func (es *ExpressionStatement) Dialect() string        { return es.Token.Dialect }
+func (es *ExpressionStatement) EndPos() oq_token.Position {
+	if es.Expression != nil {
+		return es.Expression.EndPos()
+	}
+	return es.Token.End
+}
 func (es *ExpressionStatement) String() string {
 	if es.Expression != nil {
 		return es.Expression.String()
@@ -102,20 +179,42 @@ func (es *ExpressionStatement) String() string {
 type IntegerLiteral struct {
 	Token oq_token.Token
 	Value int64
+	Big   *big.Int // set instead of Value when the literal does not fit in an int64
 }
 
-func (il *IntegerLiteral) expressionNode()      {}
-func (il *IntegerLiteral) TokenLiteral() string { return il.Token.Literal }
-func (il *IntegerLiteral) String() string       { return il.Token.Literal }
+func (il *IntegerLiteral) expressionNode()           {}
+func (il *IntegerLiteral) TokenLiteral() string      { return il.Token.Literal }
+func (il *IntegerLiteral) Pos() oq_token.Position    { return il.Token.Position }
+func (il *IntegerLiteral) EndPos() oq_token.Position { return il.Token.End }
+func (il *IntegerLiteral) Dialect() string           { return il.Token.Dialect }
+func (il *IntegerLiteral) String() string            { return il.Token.Literal }
 
 type FloatLiteral struct {
 	Token oq_token.Token
 	Value float64
 }
 
-func (il *FloatLiteral) expressionNode()      {}
-func (il *FloatLiteral) TokenLiteral() string { return il.Token.Literal }
-func (il *FloatLiteral) String() string       { return il.Token.Literal }
+func (il *FloatLiteral) expressionNode()           {}
+func (il *FloatLiteral) TokenLiteral() string      { return il.Token.Literal }
+func (il *FloatLiteral) Pos() oq_token.Position    { return il.Token.Position }
+func (il *FloatLiteral) EndPos() oq_token.Position { return il.Token.End }
+func (il *FloatLiteral) Dialect() string           { return il.Token.Dialect }
+func (il *FloatLiteral) String() string            { return il.Token.Literal }
+
+// DecimalLiteral is an exact decimal number such as 2.50d. Value is the
+// number as written, without the d suffix; the evaluator reads it so that the
+// digits after the point are kept, and 2.50d and 2.5d print differently.
+type DecimalLiteral struct {
+	Token oq_token.Token
+	Value string
+}
+
+func (dl *DecimalLiteral) expressionNode()           {}
+func (dl *DecimalLiteral) TokenLiteral() string      { return dl.Token.Literal }
+func (dl *DecimalLiteral) Pos() oq_token.Position    { return dl.Token.Position }
+func (dl *DecimalLiteral) EndPos() oq_token.Position { return dl.Token.End }
+func (dl *DecimalLiteral) Dialect() string           { return dl.Token.Dialect }
+func (dl *DecimalLiteral) String() string            { return dl.Token.Literal }
 
 type PrefixExpression struct {
 	Token    oq_token.Token
@@ -123,8 +222,11 @@ type PrefixExpression struct {
 	Right    Expression
 }
 
-func (pe *PrefixExpression) expressionNode()      {}
-func (pe *PrefixExpression) TokenLiteral() string { return pe.Token.Literal }
+func (pe *PrefixExpression) expressionNode()           {}
+func (pe *PrefixExpression) TokenLiteral() string      { return pe.Token.Literal }
+func (pe *PrefixExpression) Pos() oq_token.Position    { return pe.Token.Position }
+func (pe *PrefixExpression) EndPos() oq_token.Position { return pe.Right.EndPos() }
+func (pe *PrefixExpression) Dialect() string           { return pe.Token.Dialect }
 func (pe *PrefixExpression) String() string {
 	var out bytes.Buffer
 	out.WriteString("(")
@@ -141,8 +243,11 @@ type InfixExpression struct {
 	Right    Expression
 }
 
-func (oe *InfixExpression) expressionNode()      {}
-func (oe *InfixExpression) TokenLiteral() string { return oe.Token.Literal }
+func (oe *InfixExpression) expressionNode()           {}
+func (oe *InfixExpression) TokenLiteral() string      { return oe.Token.Literal }
+func (oe *InfixExpression) Pos() oq_token.Position    { return oe.Token.Position }
+func (oe *InfixExpression) EndPos() oq_token.Position { return oe.Right.EndPos() }
+func (oe *InfixExpression) Dialect() string           { return oe.Token.Dialect }
 func (oe *InfixExpression) String() string {
 	var out bytes.Buffer
 	out.WriteString("(")
@@ -158,17 +263,24 @@ type Boolean struct {
 	Value bool
 }
 
-func (b *Boolean) expressionNode()      {}
-func (b *Boolean) TokenLiteral() string { return b.Token.Literal }
-func (b *Boolean) String() string       { return b.Token.Literal }
+func (b *Boolean) expressionNode()           {}
+func (b *Boolean) TokenLiteral() string      { return b.Token.Literal }
+func (b *Boolean) Pos() oq_token.Position    { return b.Token.Position }
+func (b *Boolean) EndPos() oq_token.Position { return b.Token.End }
+func (b *Boolean) Dialect() string           { return b.Token.Dialect }
+func (b *Boolean) String() string            { return b.Token.Literal }
 
 type BlockStatement struct {
 	Token      oq_token.Token // the { token
 	Statements []Statement
+	Closing    oq_token.Token // the } token
 }
 
-func (bs *BlockStatement) statementNode()       {}
-func (bs *BlockStatement) TokenLiteral() string { return bs.Token.Literal }
+func (bs *BlockStatement) statementNode()            {}
+func (bs *BlockStatement) TokenLiteral() string      { return bs.Token.Literal }
+func (bs *BlockStatement) Pos() oq_token.Position    { return bs.Token.Position }
+func (bs *BlockStatement) EndPos() oq_token.Position { return bs.Closing.End }
+func (bs *BlockStatement) Dialect() string           { return bs.Token.Dialect }
 func (bs *BlockStatement) String() string {
 	var out bytes.Buffer
 	for _, s := range bs.Statements {
@@ -177,21 +289,41 @@ func (bs *BlockStatement) String() string {
 	return out.String()
 }
 
-type IfExpression struct {
-	Token       oq_token.Token
+// ConditionalBranch is a single `if` or `elsif` arm of an IfExpression.
+type ConditionalBranch struct {
+	Token       oq_token.Token // the 'if' or 'elsif' token
 	Condition   Expression
 	Consequence *BlockStatement
+}
+
+type IfExpression struct {
+	Token       oq_token.Token
+	Branches    []*ConditionalBranch // the `if` arm followed by every `elsif` arm, in source order
 	Alternative *BlockStatement
 }
 
-func (ie *IfExpression) expressionNode()      {}
-func (ie *IfExpression) TokenLiteral() string { return ie.Token.Literal }
+func (ie *IfExpression) expressionNode()        {}
+func (ie *IfExpression) TokenLiteral() string   { return ie.Token.Literal }
+func (ie *IfExpression) Pos() oq_token.Position { return ie.Token.Position }
+func (ie *IfExpression) Dialect() string        { return ie.Token.Dialect }
+func (ie *IfExpression) EndPos() oq_token.Position {
+	if ie.Alternative != nil {
+		return ie.Alternative.EndPos()
+	}
+	return ie.Branches[len(ie.Branches)-1].Consequence.EndPos()
+}
 func (ie *IfExpression) String() string {
 	var out bytes.Buffer
-	out.WriteString("if")
-	out.WriteString(ie.Condition.String())
-	out.WriteString(" ")
-	out.WriteString(ie.Consequence.String())
+	for i, branch := range ie.Branches {
+		if i == 0 {
+			out.WriteString("if")
+		} else {
+			out.WriteString("elsif")
+		}
+		out.WriteString(branch.Condition.String())
+		out.WriteString(" ")
+		out.WriteString(branch.Consequence.String())
+	}
 	if ie.Alternative != nil {
 		out.WriteString("else ")
 		out.WriteString(ie.Alternative.String())
@@ -199,14 +331,61 @@ func (ie *IfExpression) String() string {
 	return out.String()
 }
 
+type WhileStatement struct {
+	Token     oq_token.Token // the 'while' token
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func (ws *WhileStatement) statementNode()            {}
+func (ws *WhileStatement) TokenLiteral() string      { return ws.Token.Literal }
+func (ws *WhileStatement) Pos() oq_token.Position    { return ws.Token.Position }
+func (ws *WhileStatement) EndPos() oq_token.Position { return ws.Body.EndPos() }
+func (ws *WhileStatement) Dialect() string           { return ws.Token.Dialect }
+func (ws *WhileStatement) String() string {
+	var out bytes.Buffer
+	out.WriteString("while")
+	out.WriteString(ws.Condition.String())
+	out.WriteString(" ")
+	out.WriteString(ws.Body.String())
+	return out.String()
+}
+
+type ForStatement struct {
+	Token    oq_token.Token // the 'for' token
+	Variable *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (fs *ForStatement) statementNode()            {}
+func (fs *ForStatement) TokenLiteral() string      { return fs.Token.Literal }
+func (fs *ForStatement) Pos() oq_token.Position    { return fs.Token.Position }
+func (fs *ForStatement) EndPos() oq_token.Position { return fs.Body.EndPos() }
+func (fs *ForStatement) Dialect() string           { return fs.Token.Dialect }
+func (fs *ForStatement) String() string {
+	var out bytes.Buffer
+	out.WriteString("for")
+	out.WriteString("(")
+	out.WriteString(fs.Variable.String())
+	out.WriteString(" in ")
+	out.WriteString(fs.Iterable.String())
+	out.WriteString(") ")
+	out.WriteString(fs.Body.String())
+	return out.String()
+}
+
 type FunctionLiteral struct {
 	Token      oq_token.Token
 	Parameters []*Identifier
 	Body       *BlockStatement
 }
 
-func (fl *FunctionLiteral) expressionNode()      {}
-func (fl *FunctionLiteral) TokenLiteral() string { return fl.Token.Literal }
+func (fl *FunctionLiteral) expressionNode()           {}
+func (fl *FunctionLiteral) TokenLiteral() string      { return fl.Token.Literal }
+func (fl *FunctionLiteral) Pos() oq_token.Position    { return fl.Token.Position }
+func (fl *FunctionLiteral) EndPos() oq_token.Position { return fl.Body.EndPos() }
+func (fl *FunctionLiteral) Dialect() string           { return fl.Token.Dialect }
 func (fl *FunctionLiteral) String() string {
 	var out bytes.Buffer
 	params := []string{}
@@ -221,14 +400,20 @@ func (fl *FunctionLiteral) String() string {
 	return out.String()
 }
 
+// CallExpression reports the position of the called expression rather than
+// the '(' token, so errors point at the function name.
 type CallExpression struct {
 	Token     oq_token.Token // The '(' token
 	Function  Expression     // Identifier or FunctionLiteral
 	Arguments []Expression
+	Closing   oq_token.Token // the ')' token
 }
 
-func (ce *CallExpression) expressionNode()      {}
-func (ce *CallExpression) TokenLiteral() string { return ce.Token.Literal }
+func (ce *CallExpression) expressionNode()           {}
+func (ce *CallExpression) TokenLiteral() string      { return ce.Token.Literal }
+func (ce *CallExpression) Pos() oq_token.Position    { return ce.Function.Pos() }
+func (ce *CallExpression) EndPos() oq_token.Position { return ce.Closing.End }
+func (ce *CallExpression) Dialect() string           { return ce.Function.Dialect() }
 func (ce *CallExpression) String() string {
 	var out bytes.Buffer
 	args := []string{}
@@ -247,6 +432,138 @@ type StringLiteral struct {
 	Value string
 }
 
-func (sl *StringLiteral) expressionNode()      {}
-func (sl *StringLiteral) TokenLiteral() string { return sl.Token.Literal }
-func (sl *StringLiteral) String() string       { return sl.Token.Literal }
+func (sl *StringLiteral) expressionNode()           {}
+func (sl *StringLiteral) TokenLiteral() string      { return sl.Token.Literal }
+func (sl *StringLiteral) Pos() oq_token.Position    { return sl.Token.Position }
+func (sl *StringLiteral) EndPos() oq_token.Position { return sl.Token.End }
+func (sl *StringLiteral) Dialect() string           { return sl.Token.Dialect }
+func (sl *StringLiteral) String() string            { return sl.Token.Literal }
+
+// InterpolatedString is a string literal with embedded `${...}` expressions.
+// Parts holds the text between them as *StringLiteral nodes, in order.
+type InterpolatedString struct {
+	Token oq_token.Token // the oq_token.INTERPOLATED token
+	Parts []Expression
+}
+
+func (is *InterpolatedString) expressionNode()           {}
+func (is *InterpolatedString) TokenLiteral() string      { return is.Token.Literal }
+func (is *InterpolatedString) Pos() oq_token.Position    { return is.Token.Position }
+func (is *InterpolatedString) EndPos() oq_token.Position { return is.Token.End }
+func (is *InterpolatedString) Dialect() string           { return is.Token.Dialect }
+func (is *InterpolatedString) String() string {
+	var out bytes.Buffer
+	for _, part := range is.Parts {
+		if literal, ok := part.(*StringLiteral); ok {
+			out.WriteString(literal.Value)
+			continue
+		}
+		out.WriteString("${" + part.String() + "}")
+	}
+	return out.String()
+}
+
+type ArrayLiteral struct {
+	Token    oq_token.Token // the '[' token
+	Elements []Expression
+	Closing  oq_token.Token // the ']' token
+}
+
+func (al *ArrayLiteral) expressionNode()           {}
+func (al *ArrayLiteral) TokenLiteral() string      { return al.Token.Literal }
+func (al *ArrayLiteral) Pos() oq_token.Position    { return al.Token.Position }
+func (al *ArrayLiteral) EndPos() oq_token.Position { return al.Closing.End }
+func (al *ArrayLiteral) Dialect() string           { return al.Token.Dialect }
+func (al *ArrayLiteral) String() string {
+	var out bytes.Buffer
+	elements := []string{}
+	for _, el := range al.Elements {
+		elements = append(elements, el.String())
+	}
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+	return out.String()
+}
+
+type IndexExpression struct {
+	Token   oq_token.Token // the '[' token
+	Left    Expression
+	Index   Expression
+	Closing oq_token.Token // the ']' token
+}
+
+func (ie *IndexExpression) expressionNode()           {}
+func (ie *IndexExpression) TokenLiteral() string      { return ie.Token.Literal }
+func (ie *IndexExpression) Pos() oq_token.Position    { return ie.Token.Position }
+func (ie *IndexExpression) EndPos() oq_token.Position { return ie.Closing.End }
+func (ie *IndexExpression) Dialect() string           { return ie.Token.Dialect }
+func (ie *IndexExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("(")
+	out.WriteString(ie.Left.String())
+	out.WriteString("[")
+	out.WriteString(ie.Index.String())
+	out.WriteString("])")
+	return out.String()
+}
+
+// SliceExpression is `left[start:end]`. Start and End are nil when omitted,
+// as in `s[:2]` or `s[1:]`.
+type SliceExpression struct {
+	Token   oq_token.Token // the '[' token
+	Left    Expression
+	Start   Expression
+	End     Expression
+	Closing oq_token.Token // the ']' token
+}
+
+func (se *SliceExpression) expressionNode()           {}
+func (se *SliceExpression) TokenLiteral() string      { return se.Token.Literal }
+func (se *SliceExpression) Pos() oq_token.Position    { return se.Token.Position }
+func (se *SliceExpression) EndPos() oq_token.Position { return se.Closing.End }
+func (se *SliceExpression) Dialect() string           { return se.Token.Dialect }
+func (se *SliceExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("(")
+	out.WriteString(se.Left.String())
+	out.WriteString("[")
+	if se.Start != nil {
+		out.WriteString(se.Start.String())
+	}
+	out.WriteString(":")
+	if se.End != nil {
+		out.WriteString(se.End.String())
+	}
+	out.WriteString("])")
+	return out.String()
+}
+
+// HashPair is a single `key: value` entry of a HashLiteral.
+type HashPair struct {
+	Key   Expression
+	Value Expression
+}
+
+type HashLiteral struct {
+	Token   oq_token.Token // the '{' token
+	Pairs   []HashPair     // entries in source order
+	Closing oq_token.Token // the '}' token
+}
+
+func (hl *HashLiteral) expressionNode()           {}
+func (hl *HashLiteral) TokenLiteral() string      { return hl.Token.Literal }
+func (hl *HashLiteral) Pos() oq_token.Position    { return hl.Token.Position }
+func (hl *HashLiteral) EndPos() oq_token.Position { return hl.Closing.End }
+func (hl *HashLiteral) Dialect() string           { return hl.Token.Dialect }
+func (hl *HashLiteral) String() string {
+	var out bytes.Buffer
+	pairs := []string{}
+	for _, pair := range hl.Pairs {
+		pairs = append(pairs, pair.Key.String()+": "+pair.Value.String())
+	}
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+	return out.String()
+}
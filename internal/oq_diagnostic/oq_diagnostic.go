@@ -0,0 +1,208 @@
+package oq_diagnostic
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/adamerikoff/oq/internal/oq_token"
+)
+
+type Severity int
+
+const (
+	ERROR Severity = iota
+	WARNING
+	NOTE
+)
+
+func (s Severity) String() string {
+	return s.label(DEFAULT_DIALECT)
+}
+
+func (s Severity) label(dialect string) string {
+	l := labels(dialect)
+	switch s {
+	case WARNING:
+		return l.Warning
+	case NOTE:
+		return l.Note
+	default:
+		return l.Error
+	}
+}
+
+// Code identifies the kind of a diagnostic independently of its wording.
+// Lexer codes start with L, parser codes with P and runtime codes with R.
+type Code string
+
+const (
+	// Lexer
+	ILLEGAL_CHARACTER          Code = "L001"
+	UNTERMINATED_COMMENT       Code = "L002"
+	UNTERMINATED_STRING        Code = "L003"
+	INVALID_ESCAPE             Code = "L004"
+	UNTERMINATED_INTERPOLATION Code = "L005"
+	MALFORMED_NUMBER           Code = "L006"
+	NON_ASCII_DIGIT            Code = "L007"
+
+	// Parser
+	UNEXPECTED_TOKEN      Code = "P001"
+	MISSING_EXPRESSION    Code = "P002"
+	INVALID_INTEGER       Code = "P003"
+	INVALID_FLOAT         Code = "P004"
+	INVALID_ASSIGN_TARGET Code = "P005"
+	MISSING_DIALECT_NAME  Code = "P006"
+	UNKNOWN_DIALECT       Code = "P007"
+	EMPTY_INTERPOLATION   Code = "P008"
+	INVALID_DECIMAL       Code = "P009"
+
+	// Runtime
+	TYPE_MISMATCH           Code = "R001"
+	UNKNOWN_OPERATOR        Code = "R002"
+	IDENTIFIER_NOT_FOUND    Code = "R003"
+	UNDECLARED_ASSIGNMENT   Code = "R004"
+	NOT_A_FUNCTION          Code = "R005"
+	NOT_ITERABLE            Code = "R006"
+	INDEX_NOT_SUPPORTED     Code = "R007"
+	UNUSABLE_HASH_KEY       Code = "R008"
+	WRONG_ARGUMENT_COUNT    Code = "R009"
+	UNSUPPORTED_ARGUMENT    Code = "R010"
+	UNKNOWN_PREFIX_OPERATOR Code = "R011"
+	WRONG_ARGUMENT_TYPE     Code = "R012"
+	INVALID_SLICE_INDEX     Code = "R013"
+	UNSORTABLE_ELEMENT      Code = "R014"
+	INVALID_NUMBER_TEXT     Code = "R015"
+	DIVISION_BY_ZERO        Code = "R016"
+	UNKNOWN_ROUNDING_MODE   Code = "R017"
+	PLACES_OUT_OF_RANGE     Code = "R018"
+	NOT_A_NUMBER            Code = "R019"
+	INDEX_OUT_OF_RANGE      Code = "R020"
+	COUNT_TOO_LARGE         Code = "R021"
+)
+
+// Span covers the source text a diagnostic refers to. End points just past
+// the last character; a zero End means the span is a single point.
+type Span struct {
+	Start oq_token.Position
+	End   oq_token.Position
+}
+
+// TokenSpan returns the span covered by tok.
+func TokenSpan(tok oq_token.Token) Span {
+	return Span{Start: tok.Position, End: tok.End}
+}
+
+type Diagnostic struct {
+	Code     Code
+	Severity Severity
+	Span     Span
+	Message  string
+	Hint     string        // optional suggestion on how to fix the problem
+	Args     []interface{} // values substituted into the message and hint templates
+	Dialect  string        // dialect Message and Hint are written in
+}
+
+// New builds an error-severity diagnostic whose message and hint come from
+// the catalog entry for code in dialect.
+func New(code Code, span Span, dialect string, args ...interface{}) Diagnostic {
+	return Diagnostic{
+		Code:     code,
+		Severity: ERROR,
+		Span:     span,
+		Message:  Message(dialect, code, args...),
+		Hint:     Hint(dialect, code, args...),
+		Args:     args,
+		Dialect:  dialect,
+	}
+}
+
+// Localize returns a copy of the diagnostic rendered in dialect.
+func (d Diagnostic) Localize(dialect string) Diagnostic {
+	d.Message = Message(dialect, d.Code, d.Args...)
+	d.Hint = Hint(dialect, d.Code, d.Args...)
+	d.Dialect = dialect
+	return d
+}
+
+// String renders the diagnostic on a single line, prefixed with its position.
+func (d Diagnostic) String() string {
+	return d.location() + ": " + d.Message
+}
+
+func (d Diagnostic) location() string {
+	return fmt.Sprintf(labels(d.Dialect).Location, d.Span.Start.Line, d.Span.Start.Column)
+}
+
+// Render formats the diagnostic for humans: a header with severity and code,
+// the offending source line and a caret underline beneath the span.
+// source must be the full text the positions refer to.
+func (d Diagnostic) Render(source string) string {
+	var out bytes.Buffer
+
+	out.WriteString(fmt.Sprintf("%s[%s]: %s\n", d.Severity.label(d.Dialect), d.Code, d.Message))
+	out.WriteString(fmt.Sprintf("  --> %s\n", d.location()))
+
+	if line, ok := sourceLine(source, d.Span.Start.Line); ok {
+		gutter := fmt.Sprintf("%d", d.Span.Start.Line)
+		padding := strings.Repeat(" ", len(gutter))
+
+		out.WriteString(fmt.Sprintf(" %s |\n", padding))
+		out.WriteString(fmt.Sprintf(" %s | %s\n", gutter, line))
+		out.WriteString(fmt.Sprintf(" %s | %s%s\n", padding, caretIndent(line, d.Span.Start.Column), strings.Repeat("^", d.width(line))))
+	}
+
+	if d.Hint != "" {
+		out.WriteString(fmt.Sprintf("  = %s: %s\n", labels(d.Dialect).Hint, d.Hint))
+	}
+
+	return out.String()
+}
+
+// width is the number of carets to draw: the span's length when it stays on
+// one line, otherwise up to the end of the line, and never less than one.
+func (d Diagnostic) width(line string) int {
+	if d.Span.End.Line == 0 {
+		return 1
+	}
+	start := d.Span.Start.Column
+	end := d.Span.End.Column
+	if d.Span.End.Line != d.Span.Start.Line {
+		end = utf8.RuneCountInString(line) + 1
+	}
+	if end-start < 1 {
+		return 1
+	}
+	return end - start
+}
+
+// caretIndent reproduces the whitespace in front of column so the caret lines
+// up under tabs as well as spaces.
+func caretIndent(line string, column int) string {
+	var out bytes.Buffer
+	i := 1
+	for _, r := range line {
+		if i >= column {
+			break
+		}
+		if r == '\t' {
+			out.WriteRune('\t')
+		} else {
+			out.WriteRune(' ')
+		}
+		i++
+	}
+	for ; i < column; i++ {
+		out.WriteRune(' ')
+	}
+	return out.String()
+}
+
+func sourceLine(source string, line int) (string, bool) {
+	lines := strings.Split(source, "\n")
+	if line < 1 || line > len(lines) {
+		return "", false
+	}
+	return strings.TrimRight(lines[line-1], "\r"), true
+}
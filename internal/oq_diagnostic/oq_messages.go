@@ -0,0 +1,468 @@
+package oq_diagnostic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DEFAULT_DIALECT is used whenever a dialect has no translation for a message.
+const DEFAULT_DIALECT = "eng"
+
+// Messages holds the message template of every diagnostic code per dialect.
+// Templates are fmt format strings filled with the diagnostic's Args; they may
+// use explicit argument indexes such as %[2]s to reorder or skip arguments.
+var Messages = map[string]map[Code]string{
+	"eng": {
+		ILLEGAL_CHARACTER:          "illegal character %q",
+		UNTERMINATED_COMMENT:       "unterminated block comment",
+		UNTERMINATED_STRING:        "unterminated string literal, expected closing %s",
+		INVALID_ESCAPE:             "invalid escape sequence `%s`",
+		UNTERMINATED_INTERPOLATION: "unterminated interpolation, expected closing `}`",
+		MALFORMED_NUMBER:           "malformed number %q",
+		NON_ASCII_DIGIT:            "number %q uses digits other than 0-9",
+
+		UNEXPECTED_TOKEN:      "expected next token to be %s, got %s instead",
+		MISSING_EXPRESSION:    "no prefix parse function for %s found",
+		INVALID_INTEGER:       "could not parse %q as integer",
+		INVALID_FLOAT:         "could not parse %q as float",
+		INVALID_DECIMAL:       "could not parse %[1]q as decimal",
+		INVALID_ASSIGN_TARGET: "cannot assign to %s",
+		MISSING_DIALECT_NAME:  "expected dialect name after '~', got %s",
+		UNKNOWN_DIALECT:       "unknown dialect '%[1]s'",
+		EMPTY_INTERPOLATION:   "empty interpolation `${}`",
+
+		TYPE_MISMATCH:           "type mismatch: %s %s %s",
+		UNKNOWN_OPERATOR:        "unknown operator: %s %s %s",
+		IDENTIFIER_NOT_FOUND:    "identifier not found: %s",
+		UNDECLARED_ASSIGNMENT:   "assignment to undeclared identifier: %s",
+		NOT_A_FUNCTION:          "not a function: %s",
+		NOT_ITERABLE:            "not iterable: %s",
+		INDEX_NOT_SUPPORTED:     "index operator not supported: %s",
+		UNUSABLE_HASH_KEY:       "unusable as hash key: %s",
+		WRONG_ARGUMENT_COUNT:    "wrong number of arguments. got=%d, want=%v",
+		UNSUPPORTED_ARGUMENT:    "argument to `%s` not supported, got %s",
+		UNKNOWN_PREFIX_OPERATOR: "unknown operator: %s%s",
+		WRONG_ARGUMENT_TYPE:     "argument to `%s` must be %s, got %s",
+		INVALID_SLICE_INDEX:     "slice bounds must be INTEGER, got %s",
+		UNSORTABLE_ELEMENT:      "`%s` sorts only strings or only numbers, got %s",
+		INVALID_NUMBER_TEXT:     "`%s` cannot read %q as a number",
+		DIVISION_BY_ZERO:        "division by zero",
+		UNKNOWN_ROUNDING_MODE:   "`%s` does not know the rounding mode %q",
+		PLACES_OUT_OF_RANGE:     "`%s` cannot use %d places; the limit is %d",
+		NOT_A_NUMBER:            "argument to `%s` must be a number, got %s",
+		INDEX_OUT_OF_RANGE:      "index out of range: %s",
+		COUNT_TOO_LARGE:         "loop count too large: %s",
+	},
+	"qzq": {
+		ILLEGAL_CHARACTER:          "рұқсат етілмеген таңба %q",
+		UNTERMINATED_COMMENT:       "блоктық түсініктеме жабылмаған",
+		UNTERMINATED_STRING:        "жол литералы жабылмаған, жабатын %s күтілді",
+		INVALID_ESCAPE:             "жарамсыз escape-тізбек `%s`",
+		UNTERMINATED_INTERPOLATION: "интерполяция жабылмаған, жабатын `}` күтілді",
+		MALFORMED_NUMBER:           "қате жазылған сан %q",
+		NON_ASCII_DIGIT:            "%q санында 0-9 цифрларынан басқа цифрлар бар",
+
+		UNEXPECTED_TOKEN:      "келесі лексема %s болуы керек еді, оның орнына %s алынды",
+		MISSING_EXPRESSION:    "%s үшін префикстік талдау функциясы табылмады",
+		INVALID_INTEGER:       "%q бүтін сан ретінде талданбады",
+		INVALID_FLOAT:         "%q бөлшек сан ретінде талданбады",
+		INVALID_DECIMAL:       "%[1]q ондық сан ретінде талданбады",
+		INVALID_ASSIGN_TARGET: "%s мәнін тағайындау мүмкін емес",
+		MISSING_DIALECT_NAME:  "'~' белгісінен кейін диалект атауы күтілді, %s алынды",
+		UNKNOWN_DIALECT:       "белгісіз диалект '%[1]s'",
+		EMPTY_INTERPOLATION:   "бос интерполяция `${}`",
+
+		TYPE_MISMATCH:           "түрлер сәйкес емес: %s %s %s",
+		UNKNOWN_OPERATOR:        "белгісіз оператор: %s %s %s",
+		IDENTIFIER_NOT_FOUND:    "идентификатор табылмады: %s",
+		UNDECLARED_ASSIGNMENT:   "жарияланбаған идентификаторға мән беру: %s",
+		NOT_A_FUNCTION:          "функция емес: %s",
+		NOT_ITERABLE:            "бойымен жүруге болмайды: %s",
+		INDEX_NOT_SUPPORTED:     "индекс операторына қолдау көрсетілмейді: %s",
+		UNUSABLE_HASH_KEY:       "хэш кілті ретінде пайдалануға болмайды: %s",
+		WRONG_ARGUMENT_COUNT:    "аргументтердің қате саны. алды=%d, келеді=%v",
+		UNSUPPORTED_ARGUMENT:    "`%s` аргументіне қолдау көрсетілмейді, %s алынды",
+		UNKNOWN_PREFIX_OPERATOR: "белгісіз оператор: %s%s",
+		WRONG_ARGUMENT_TYPE:     "`%s` аргументі %s болуы керек, %s алынды",
+		INVALID_SLICE_INDEX:     "кесінді шекаралары INTEGER болуы керек, %s алынды",
+		UNSORTABLE_ELEMENT:      "`%s` тек жолдарды немесе тек сандарды сұрыптайды, %s алынды",
+		INVALID_NUMBER_TEXT:     "`%s` %q мәтінін сан ретінде оқи алмайды",
+		DIVISION_BY_ZERO:        "нөлге бөлу",
+		UNKNOWN_ROUNDING_MODE:   "`%s` %q дөңгелектеу тәсілін білмейді",
+		PLACES_OUT_OF_RANGE:     "`%s` %d орынды қолдана алмайды; шегі %d",
+		NOT_A_NUMBER:            "`%s` аргументі сан болуы керек, %s алынды",
+		INDEX_OUT_OF_RANGE:      "индекс ауқымнан тыс: %s",
+		COUNT_TOO_LARGE:         "цикл саны тым үлкен: %s",
+	},
+	"trk": {
+		ILLEGAL_CHARACTER:          "geçersiz karakter %q",
+		UNTERMINATED_COMMENT:       "kapatılmamış blok yorumu",
+		UNTERMINATED_STRING:        "kapatılmamış dize, kapanış %s bekleniyordu",
+		INVALID_ESCAPE:             "geçersiz kaçış dizisi `%s`",
+		UNTERMINATED_INTERPOLATION: "kapatılmamış ara değer, kapanış `}` bekleniyordu",
+		MALFORMED_NUMBER:           "hatalı yazılmış sayı %q",
+		NON_ASCII_DIGIT:            "%q sayısı 0-9 dışında rakamlar içeriyor",
+
+		UNEXPECTED_TOKEN:      "sonraki belirtecin %s olması bekleniyordu, bunun yerine %s geldi",
+		MISSING_EXPRESSION:    "%s için önek ayrıştırma fonksiyonu bulunamadı",
+		INVALID_INTEGER:       "%q tam sayı olarak ayrıştırılamadı",
+		INVALID_FLOAT:         "%q ondalık sayı olarak ayrıştırılamadı",
+		INVALID_DECIMAL:       "%[1]q kesin ondalık sayı olarak ayrıştırılamadı",
+		INVALID_ASSIGN_TARGET: "%s öğesine atama yapılamaz",
+		MISSING_DIALECT_NAME:  "'~' işaretinden sonra lehçe adı bekleniyordu, %s geldi",
+		UNKNOWN_DIALECT:       "bilinmeyen lehçe '%[1]s'",
+		EMPTY_INTERPOLATION:   "boş ara değer `${}`",
+
+		TYPE_MISMATCH:           "tür uyuşmazlığı: %s %s %s",
+		UNKNOWN_OPERATOR:        "bilinmeyen operatör: %s %s %s",
+		IDENTIFIER_NOT_FOUND:    "tanımlayıcı bulunamadı: %s",
+		UNDECLARED_ASSIGNMENT:   "tanımlanmamış tanımlayıcıya atama: %s",
+		NOT_A_FUNCTION:          "fonksiyon değil: %s",
+		NOT_ITERABLE:            "üzerinde yinelenemez: %s",
+		INDEX_NOT_SUPPORTED:     "indeks operatörü desteklenmiyor: %s",
+		UNUSABLE_HASH_KEY:       "hash anahtarı olarak kullanılamaz: %s",
+		WRONG_ARGUMENT_COUNT:    "yanlış sayıda argüman. verilen=%d, beklenen=%v",
+		UNSUPPORTED_ARGUMENT:    "`%s` argümanı desteklenmiyor, %s alındı",
+		UNKNOWN_PREFIX_OPERATOR: "bilinmeyen operatör: %s%s",
+		WRONG_ARGUMENT_TYPE:     "`%s` argümanı %s olmalı, %s alındı",
+		INVALID_SLICE_INDEX:     "dilim sınırları INTEGER olmalı, %s alındı",
+		UNSORTABLE_ELEMENT:      "`%s` yalnızca dizeleri ya da yalnızca sayıları sıralar, %s alındı",
+		INVALID_NUMBER_TEXT:     "`%s`, %q değerini sayı olarak okuyamıyor",
+		DIVISION_BY_ZERO:        "sıfıra bölme",
+		UNKNOWN_ROUNDING_MODE:   "`%s`, %q yuvarlama kipini bilmiyor",
+		PLACES_OUT_OF_RANGE:     "`%s` %d basamak kullanamaz; sınır %d",
+		NOT_A_NUMBER:            "`%s` argümanı bir sayı olmalı, %s alındı",
+		INDEX_OUT_OF_RANGE:      "indeks aralık dışında: %s",
+		COUNT_TOO_LARGE:         "döngü sayısı çok büyük: %s",
+	},
+	"rus": {
+		ILLEGAL_CHARACTER:          "недопустимый символ %q",
+		UNTERMINATED_COMMENT:       "незакрытый блочный комментарий",
+		UNTERMINATED_STRING:        "незакрытый строковый литерал, ожидалась закрывающая %s",
+		INVALID_ESCAPE:             "недопустимая escape-последовательность `%s`",
+		UNTERMINATED_INTERPOLATION: "незакрытая интерполяция, ожидалась закрывающая `}`",
+		MALFORMED_NUMBER:           "неправильно записанное число %q",
+		NON_ASCII_DIGIT:            "число %q содержит цифры, отличные от 0-9",
+
+		UNEXPECTED_TOKEN:      "ожидалась лексема %s, получено %s",
+		MISSING_EXPRESSION:    "не найдена префиксная функция разбора для %s",
+		INVALID_INTEGER:       "не удалось разобрать %q как целое число",
+		INVALID_FLOAT:         "не удалось разобрать %q как дробное число",
+		INVALID_DECIMAL:       "не удалось разобрать %[1]q как десятичное число",
+		INVALID_ASSIGN_TARGET: "нельзя присвоить значение %s",
+		MISSING_DIALECT_NAME:  "после '~' ожидалось имя диалекта, получено %s",
+		UNKNOWN_DIALECT:       "неизвестный диалект '%[1]s'",
+		EMPTY_INTERPOLATION:   "пустая интерполяция `${}`",
+
+		TYPE_MISMATCH:           "несоответствие типов: %s %s %s",
+		UNKNOWN_OPERATOR:        "неизвестный оператор: %s %s %s",
+		IDENTIFIER_NOT_FOUND:    "идентификатор не найден: %s",
+		UNDECLARED_ASSIGNMENT:   "присваивание необъявленному идентификатору: %s",
+		NOT_A_FUNCTION:          "не является функцией: %s",
+		NOT_ITERABLE:            "нельзя перебрать: %s",
+		INDEX_NOT_SUPPORTED:     "оператор индексации не поддерживается: %s",
+		UNUSABLE_HASH_KEY:       "нельзя использовать как ключ хэша: %s",
+		WRONG_ARGUMENT_COUNT:    "неверное количество аргументов. получено=%d, ожидалось=%v",
+		UNSUPPORTED_ARGUMENT:    "аргумент `%s` не поддерживается, получено %s",
+		UNKNOWN_PREFIX_OPERATOR: "неизвестный оператор: %s%s",
+		WRONG_ARGUMENT_TYPE:     "аргумент `%s` должен быть %s, получено %s",
+		INVALID_SLICE_INDEX:     "границы среза должны быть INTEGER, получено %s",
+		UNSORTABLE_ELEMENT:      "`%s` сортирует только строки или только числа, получено %s",
+		INVALID_NUMBER_TEXT:     "`%s` не может прочитать %q как число",
+		DIVISION_BY_ZERO:        "деление на ноль",
+		UNKNOWN_ROUNDING_MODE:   "`%s` не знает способа округления %q",
+		PLACES_OUT_OF_RANGE:     "`%s` не может использовать %d знаков; предел %d",
+		NOT_A_NUMBER:            "аргумент `%s` должен быть числом, получено %s",
+		INDEX_OUT_OF_RANGE:      "индекс вне диапазона: %s",
+		COUNT_TOO_LARGE:         "слишком большое число повторений: %s",
+	},
+	"uzb": {
+		ILLEGAL_CHARACTER:          "notoʻgʻri belgi %q",
+		UNTERMINATED_COMMENT:       "yopilmagan blok izohi",
+		UNTERMINATED_STRING:        "yopilmagan satr literali, yopuvchi %s kutilgan edi",
+		INVALID_ESCAPE:             "notoʻgʻri escape ketma-ketligi `%s`",
+		UNTERMINATED_INTERPOLATION: "yopilmagan interpolyatsiya, yopuvchi `}` kutilgan edi",
+		MALFORMED_NUMBER:           "notoʻgʻri yozilgan son %q",
+		NON_ASCII_DIGIT:            "%q sonida 0-9 dan boshqa raqamlar bor",
+
+		UNEXPECTED_TOKEN:      "keyingi leksema %s boʻlishi kerak edi, uning oʻrniga %s keldi",
+		MISSING_EXPRESSION:    "%s uchun prefiks tahlil funksiyasi topilmadi",
+		INVALID_INTEGER:       "%q butun son sifatida tahlil qilinmadi",
+		INVALID_FLOAT:         "%q kasr son sifatida tahlil qilinmadi",
+		INVALID_DECIMAL:       "%[1]q oʻnlik son sifatida tahlil qilinmadi",
+		INVALID_ASSIGN_TARGET: "%s ga qiymat berib boʻlmaydi",
+		MISSING_DIALECT_NAME:  "'~' belgisidan keyin dialekt nomi kutilgan edi, %s keldi",
+		UNKNOWN_DIALECT:       "nomaʼlum dialekt '%[1]s'",
+		EMPTY_INTERPOLATION:   "boʻsh interpolyatsiya `${}`",
+
+		TYPE_MISMATCH:           "turlar mos emas: %s %s %s",
+		UNKNOWN_OPERATOR:        "nomaʼlum operator: %s %s %s",
+		IDENTIFIER_NOT_FOUND:    "identifikator topilmadi: %s",
+		UNDECLARED_ASSIGNMENT:   "eʼlon qilinmagan identifikatorga qiymat berish: %s",
+		NOT_A_FUNCTION:          "funksiya emas: %s",
+		NOT_ITERABLE:            "takrorlab boʻlmaydi: %s",
+		INDEX_NOT_SUPPORTED:     "indeks operatori qoʻllab-quvvatlanmaydi: %s",
+		UNUSABLE_HASH_KEY:       "xesh kaliti sifatida ishlatib boʻlmaydi: %s",
+		WRONG_ARGUMENT_COUNT:    "argumentlar soni notoʻgʻri. berildi=%d, kutilgan=%v",
+		UNSUPPORTED_ARGUMENT:    "`%s` argumenti qoʻllab-quvvatlanmaydi, %s berildi",
+		UNKNOWN_PREFIX_OPERATOR: "nomaʼlum operator: %s%s",
+		WRONG_ARGUMENT_TYPE:     "`%s` argumenti %s boʻlishi kerak, %s berildi",
+		INVALID_SLICE_INDEX:     "kesim chegaralari INTEGER boʻlishi kerak, %s berildi",
+		UNSORTABLE_ELEMENT:      "`%s` faqat satrlarni yoki faqat sonlarni saralaydi, %s berildi",
+		INVALID_NUMBER_TEXT:     "`%s` %q ni son sifatida oʻqiy olmaydi",
+		DIVISION_BY_ZERO:        "nolga boʻlish",
+		UNKNOWN_ROUNDING_MODE:   "`%s` %q yaxlitlash usulini bilmaydi",
+		PLACES_OUT_OF_RANGE:     "`%s` %d xonani ishlata olmaydi; chegara %d",
+		NOT_A_NUMBER:            "`%s` argumenti son boʻlishi kerak, %s berildi",
+		INDEX_OUT_OF_RANGE:      "indeks diapazondan tashqarida: %s",
+		COUNT_TOO_LARGE:         "takrorlar soni juda katta: %s",
+	},
+	"kgz": {
+		ILLEGAL_CHARACTER:          "уруксат берилбеген белги %q",
+		UNTERMINATED_COMMENT:       "жабылбаган блоктук комментарий",
+		UNTERMINATED_STRING:        "жабылбаган сап литералы, жабуучу %s күтүлгөн",
+		INVALID_ESCAPE:             "жараксыз escape-ырааттуулук `%s`",
+		UNTERMINATED_INTERPOLATION: "жабылбаган интерполяция, жабуучу `}` күтүлгөн",
+		MALFORMED_NUMBER:           "туура эмес жазылган сан %q",
+		NON_ASCII_DIGIT:            "%q санында 0-9 цифраларынан башка цифралар бар",
+
+		UNEXPECTED_TOKEN:      "кийинки лексема %s болушу керек эле, анын ордуна %s алынды",
+		MISSING_EXPRESSION:    "%s үчүн префикстик талдоо функциясы табылган жок",
+		INVALID_INTEGER:       "%q бүтүн сан катары талданбады",
+		INVALID_FLOAT:         "%q бөлчөк сан катары талданбады",
+		INVALID_DECIMAL:       "%[1]q ондук сан катары талданбады",
+		INVALID_ASSIGN_TARGET: "%s маанисин ыйгаруу мүмкүн эмес",
+		MISSING_DIALECT_NAME:  "'~' белгисинен кийин диалект аты күтүлгөн, %s алынды",
+		UNKNOWN_DIALECT:       "белгисиз диалект '%[1]s'",
+		EMPTY_INTERPOLATION:   "бош интерполяция `${}`",
+
+		TYPE_MISMATCH:           "түрлөр дал келбейт: %s %s %s",
+		UNKNOWN_OPERATOR:        "белгисиз оператор: %s %s %s",
+		IDENTIFIER_NOT_FOUND:    "идентификатор табылган жок: %s",
+		UNDECLARED_ASSIGNMENT:   "жарыяланбаган идентификаторго маани ыйгаруу: %s",
+		NOT_A_FUNCTION:          "функция эмес: %s",
+		NOT_ITERABLE:            "кайталап өтүүгө болбойт: %s",
+		INDEX_NOT_SUPPORTED:     "индекс оператору колдоого алынбайт: %s",
+		UNUSABLE_HASH_KEY:       "хэш ачкычы катары колдонууга болбойт: %s",
+		WRONG_ARGUMENT_COUNT:    "аргументтердин саны туура эмес. алынды=%d, күтүлгөн=%v",
+		UNSUPPORTED_ARGUMENT:    "`%s` аргументи колдоого алынбайт, %s алынды",
+		UNKNOWN_PREFIX_OPERATOR: "белгисиз оператор: %s%s",
+		WRONG_ARGUMENT_TYPE:     "`%s` аргументи %s болушу керек, %s алынды",
+		INVALID_SLICE_INDEX:     "кесинди чектери INTEGER болушу керек, %s алынды",
+		UNSORTABLE_ELEMENT:      "`%s` саптарды гана же сандарды гана иреттейт, %s алынды",
+		INVALID_NUMBER_TEXT:     "`%s` %q текстин сан катары окуй албайт",
+		DIVISION_BY_ZERO:        "нөлгө бөлүү",
+		UNKNOWN_ROUNDING_MODE:   "`%s` %q тегеректөө ыкмасын билбейт",
+		PLACES_OUT_OF_RANGE:     "`%s` %d орунду колдоно албайт; чеги %d",
+		NOT_A_NUMBER:            "`%s` аргументи сан болушу керек, %s алынды",
+		INDEX_OUT_OF_RANGE:      "индекс чектен тышкары: %s",
+		COUNT_TOO_LARGE:         "кайталоо саны өтө чоң: %s",
+	},
+	"qzl": {
+		ILLEGAL_CHARACTER:          "rūqsat etılmegen tañba %q",
+		UNTERMINATED_COMMENT:       "bloktyq tüsınıkteme jabylmağan",
+		UNTERMINATED_STRING:        "jol literaly jabylmağan, jabatyn %s kütıldı",
+		INVALID_ESCAPE:             "jaramsyz escape-tızbek `%s`",
+		UNTERMINATED_INTERPOLATION: "interpoliasia jabylmağan, jabatyn `}` kütıldı",
+		MALFORMED_NUMBER:           "qate jazylğan san %q",
+		NON_ASCII_DIGIT:            "%q sanynda 0-9 sifrlarynan basqa sifrlar bar",
+
+		UNEXPECTED_TOKEN:      "kelesı leksema %s boluy kerek edı, onyñ ornyna %s alyndy",
+		MISSING_EXPRESSION:    "%s üşın prefikstık taldau funksiiasy tabylmady",
+		INVALID_INTEGER:       "%q bütın san retınde taldanbady",
+		INVALID_FLOAT:         "%q bölşek san retınde taldanbady",
+		INVALID_DECIMAL:       "%[1]q ondyq san retınde taldanbady",
+		INVALID_ASSIGN_TARGET: "%s mänın tağaiyndau mümkın emes",
+		MISSING_DIALECT_NAME:  "'~' belgısınen keiın dialekt atauy kütıldı, %s alyndy",
+		UNKNOWN_DIALECT:       "belgısız dialekt '%[1]s'",
+		EMPTY_INTERPOLATION:   "bos interpoliasia `${}`",
+
+		TYPE_MISMATCH:           "türler säikes emes: %s %s %s",
+		UNKNOWN_OPERATOR:        "belgısız operator: %s %s %s",
+		IDENTIFIER_NOT_FOUND:    "identifikator tabylmady: %s",
+		UNDECLARED_ASSIGNMENT:   "jariialanbağan identifikatorğa män beru: %s",
+		NOT_A_FUNCTION:          "funksiia emes: %s",
+		NOT_ITERABLE:            "boiymen jürüge bolmaidy: %s",
+		INDEX_NOT_SUPPORTED:     "indeks operatoryna qoldau körsetılmeidı: %s",
+		UNUSABLE_HASH_KEY:       "hesh kıltı retınde paidalanuğa bolmaidy: %s",
+		WRONG_ARGUMENT_COUNT:    "argumentterdıñ qate sany. aldy=%d, keledı=%v",
+		UNSUPPORTED_ARGUMENT:    "`%s` argumentıne qoldau körsetılmeidı, %s alyndy",
+		UNKNOWN_PREFIX_OPERATOR: "belgısız operator: %s%s",
+		WRONG_ARGUMENT_TYPE:     "`%s` argumentı %s boluy kerek, %s alyndy",
+		INVALID_SLICE_INDEX:     "kesındı şekaralary INTEGER boluy kerek, %s alyndy",
+		UNSORTABLE_ELEMENT:      "`%s` tek joldardy nemese tek sandardy sūryptaidy, %s alyndy",
+		INVALID_NUMBER_TEXT:     "`%s` %q mätının san retınde oqi almaidy",
+		DIVISION_BY_ZERO:        "nölge bölu",
+		UNKNOWN_ROUNDING_MODE:   "`%s` %q döñgelekteu täsılın bılmeidı",
+		PLACES_OUT_OF_RANGE:     "`%s` %d oryndy qoldana almaidy; şegı %d",
+		NOT_A_NUMBER:            "`%s` argumentı san boluy kerek, %s alyndy",
+		INDEX_OUT_OF_RANGE:      "indeks auqymnan tys: %s",
+		COUNT_TOO_LARGE:         "sikl sany tym ülken: %s",
+	},
+}
+
+// Hints holds the optional fix suggestion of a diagnostic code per dialect.
+// They are formatted with the same Args as the message.
+var Hints = map[string]map[Code]string{
+	"eng": {
+		INVALID_ASSIGN_TARGET: "only a variable name can appear on the left of `=`",
+		UNKNOWN_DIALECT:       "available dialects: %[2]s",
+		IDENTIFIER_NOT_FOUND:  "declare it with `let` before using it",
+		UNDECLARED_ASSIGNMENT: "declare it with `let` before assigning to it",
+		UNUSABLE_HASH_KEY:     "only strings, integers and booleans can be hash keys",
+		UNTERMINATED_COMMENT:  "close it with `*/`; block comments nest, so every `/*` needs its own `*/`",
+		UNTERMINATED_STRING:   "a \"...\" string ends on the line it starts on; use \"\"\"...\"\"\" for text that spans several lines",
+		INVALID_ESCAPE:        "valid escapes are \\n, \\t, \\r, \\0, \\\\, \\\", \\`, \\$, \\uXXXX and \\u{X...}; a `...` string keeps backslashes as written",
+		EMPTY_INTERPOLATION:   "put an expression between the braces, or write `\\${` for a literal `${`",
+		INVALID_NUMBER_TEXT:   "write numbers as in 1,234.5",
+		MALFORMED_NUMBER:      "numbers look like 42, 1_000, 3.14, 1.5e-3, 2.50d, 0xFF, 0b1010 or 0o17",
+		NON_ASCII_DIGIT:       "write numbers in code with the digits 0-9",
+		UNKNOWN_ROUNDING_MODE: "the modes are half_even, half_up, half_down, up, down, ceiling, floor",
+		INVALID_DECIMAL:       "a decimal can have at most %[2]d digits after the point, and an exponent of at most %[2]d",
+	},
+	"qzq": {
+		INVALID_ASSIGN_TARGET: "`=` белгісінің сол жағында тек айнымалы атауы тұра алады",
+		UNKNOWN_DIALECT:       "қолжетімді диалектілер: %[2]s",
+		IDENTIFIER_NOT_FOUND:  "алдымен оны `болсын` арқылы жариялаңыз",
+		UNDECLARED_ASSIGNMENT: "мән бермес бұрын оны `болсын` арқылы жариялаңыз",
+		UNUSABLE_HASH_KEY:     "хэш кілті тек жол, бүтін сан немесе логикалық мән бола алады",
+		UNTERMINATED_COMMENT:  "оны `*/` арқылы жабыңыз; түсініктемелер кірістіріледі, сондықтан әр `/*` өз `*/` белгісін қажет етеді",
+		UNTERMINATED_STRING:   "\"...\" жолы басталған жолында аяқталады; бірнеше жолға созылатын мәтін үшін \"\"\"...\"\"\" қолданыңыз",
+		INVALID_ESCAPE:        "жарамды тізбектер: \\n, \\t, \\r, \\0, \\\\, \\\", \\`, \\$, \\uXXXX және \\u{X...}; `...` жолында кері қиғаш сызықтар жазылғандай қалады",
+		EMPTY_INTERPOLATION:   "жақшалардың арасына өрнек жазыңыз немесе `${` мәтінінің өзі үшін `\\${` жазыңыз",
+		INVALID_NUMBER_TEXT:   "сандарды 1 234,5 түрінде жазыңыз",
+		MALFORMED_NUMBER:      "сандар былай жазылады: 42, 1_000, 3.14, 1.5e-3, 2.50d, 0xFF, 0b1010 немесе 0o17",
+		NON_ASCII_DIGIT:       "кодтағы сандарды 0-9 цифрларымен жазыңыз",
+		UNKNOWN_ROUNDING_MODE: "тәсілдер: half_even, half_up, half_down, up, down, ceiling, floor",
+		INVALID_DECIMAL:       "ондық санның үтірден кейін ең көбі %[2]d цифры, ал дәреже көрсеткіші ең көбі %[2]d бола алады",
+	},
+	"trk": {
+		INVALID_ASSIGN_TARGET: "`=` işaretinin solunda yalnızca bir değişken adı olabilir",
+		UNKNOWN_DIALECT:       "kullanılabilir lehçeler: %[2]s",
+		IDENTIFIER_NOT_FOUND:  "kullanmadan önce `olsun` ile tanımlayın",
+		UNDECLARED_ASSIGNMENT: "atama yapmadan önce `olsun` ile tanımlayın",
+		UNUSABLE_HASH_KEY:     "yalnızca metinler, tam sayılar ve mantıksal değerler hash anahtarı olabilir",
+		UNTERMINATED_COMMENT:  "`*/` ile kapatın; yorumlar iç içe olabilir, bu yüzden her `/*` kendi `*/` işaretini gerektirir",
+		UNTERMINATED_STRING:   "\"...\" dizesi başladığı satırda biter; birden çok satıra yayılan metin için \"\"\"...\"\"\" kullanın",
+		INVALID_ESCAPE:        "geçerli kaçışlar \\n, \\t, \\r, \\0, \\\\, \\\", \\`, \\$, \\uXXXX ve \\u{X...}; `...` dizesi ters eğik çizgileri yazıldığı gibi tutar",
+		EMPTY_INTERPOLATION:   "parantezlerin arasına bir ifade yazın ya da düz `${` için `\\${` yazın",
+		INVALID_NUMBER_TEXT:   "sayıları 1.234,5 biçiminde yazın",
+		MALFORMED_NUMBER:      "sayılar şöyle yazılır: 42, 1_000, 3.14, 1.5e-3, 2.50d, 0xFF, 0b1010 ya da 0o17",
+		NON_ASCII_DIGIT:       "koddaki sayıları 0-9 rakamlarıyla yazın",
+		UNKNOWN_ROUNDING_MODE: "kipler: half_even, half_up, half_down, up, down, ceiling, floor",
+		INVALID_DECIMAL:       "bir ondalık sayının virgülden sonra en çok %[2]d basamağı ve en çok %[2]d üssü olabilir",
+	},
+	"rus": {
+		INVALID_ASSIGN_TARGET: "слева от `=` может стоять только имя переменной",
+		UNKNOWN_DIALECT:       "доступные диалекты: %[2]s",
+		IDENTIFIER_NOT_FOUND:  "объявите его с помощью `пусть` перед использованием",
+		UNDECLARED_ASSIGNMENT: "объявите его с помощью `пусть` перед присваиванием",
+		UNUSABLE_HASH_KEY:     "ключом хэша могут быть только строки, целые числа и логические значения",
+		UNTERMINATED_COMMENT:  "закройте его с помощью `*/`; комментарии могут быть вложенными, поэтому каждому `/*` нужен свой `*/`",
+		UNTERMINATED_STRING:   "строка \"...\" должна закончиться на той же строке; для многострочного текста используйте \"\"\"...\"\"\"",
+		INVALID_ESCAPE:        "допустимы \\n, \\t, \\r, \\0, \\\\, \\\", \\`, \\$, \\uXXXX и \\u{X...}; строка `...` сохраняет обратные косые черты как есть",
+		EMPTY_INTERPOLATION:   "поместите выражение между скобками или напишите `\\${`, чтобы получить сам текст `${`",
+		INVALID_NUMBER_TEXT:   "записывайте числа в виде 1 234,5",
+		MALFORMED_NUMBER:      "числа записываются так: 42, 1_000, 3.14, 1.5e-3, 2.50d, 0xFF, 0b1010 или 0o17",
+		NON_ASCII_DIGIT:       "записывайте числа в коде цифрами 0-9",
+		UNKNOWN_ROUNDING_MODE: "способы: half_even, half_up, half_down, up, down, ceiling, floor",
+		INVALID_DECIMAL:       "у десятичного числа может быть не более %[2]d знаков после запятой и показатель степени не более %[2]d",
+	},
+	"uzb": {
+		INVALID_ASSIGN_TARGET: "`=` belgisining chap tomonida faqat oʻzgaruvchi nomi boʻlishi mumkin",
+		UNKNOWN_DIALECT:       "mavjud dialektlar: %[2]s",
+		IDENTIFIER_NOT_FOUND:  "uni ishlatishdan oldin `boʻlsin` bilan eʼlon qiling",
+		UNDECLARED_ASSIGNMENT: "qiymat berishdan oldin uni `boʻlsin` bilan eʼlon qiling",
+		UNUSABLE_HASH_KEY:     "xesh kaliti faqat satr, butun son yoki mantiqiy qiymat boʻlishi mumkin",
+		UNTERMINATED_COMMENT:  "uni `*/` bilan yoping; izohlar ichma-ich boʻlishi mumkin, shuning uchun har bir `/*` oʻz `*/` belgisini talab qiladi",
+		UNTERMINATED_STRING:   "\"...\" satri boshlangan qatorida tugaydi; bir necha qatorli matn uchun \"\"\"...\"\"\" dan foydalaning",
+		INVALID_ESCAPE:        "toʻgʻri ketma-ketliklar: \\n, \\t, \\r, \\0, \\\\, \\\", \\`, \\$, \\uXXXX va \\u{X...}; `...` satri teskari chiziqlarni yozilganicha saqlaydi",
+		EMPTY_INTERPOLATION:   "qavslar orasiga ifoda yozing yoki `${` matnining oʻzi uchun `\\${` yozing",
+		INVALID_NUMBER_TEXT:   "sonlarni 1 234,5 koʻrinishida yozing",
+		MALFORMED_NUMBER:      "sonlar shunday yoziladi: 42, 1_000, 3.14, 1.5e-3, 2.50d, 0xFF, 0b1010 yoki 0o17",
+		NON_ASCII_DIGIT:       "koddagi sonlarni 0-9 raqamlari bilan yozing",
+		UNKNOWN_ROUNDING_MODE: "usullar: half_even, half_up, half_down, up, down, ceiling, floor",
+		INVALID_DECIMAL:       "oʻnlik sonda nuqtadan keyin koʻpi bilan %[2]d raqam va koʻpi bilan %[2]d daraja boʻlishi mumkin",
+	},
+	"kgz": {
+		INVALID_ASSIGN_TARGET: "`=` белгисинин сол жагында өзгөрмөнүн аты гана тура алат",
+		UNKNOWN_DIALECT:       "жеткиликтүү диалекттер: %[2]s",
+		IDENTIFIER_NOT_FOUND:  "колдонуудан мурун аны `болсун` менен жарыялаңыз",
+		UNDECLARED_ASSIGNMENT: "маани ыйгаруудан мурун аны `болсун` менен жарыялаңыз",
+		UNUSABLE_HASH_KEY:     "хэш ачкычы сап, бүтүн сан же логикалык маани гана боло алат",
+		UNTERMINATED_COMMENT:  "аны `*/` менен жабыңыз; комментарийлер ичине салынышы мүмкүн, ошондуктан ар бир `/*` өзүнүн `*/` белгисин талап кылат",
+		UNTERMINATED_STRING:   "\"...\" сабы башталган сабында бүтөт; бир нече саптуу текст үчүн \"\"\"...\"\"\" колдонуңуз",
+		INVALID_ESCAPE:        "жарактуу ырааттуулуктар: \\n, \\t, \\r, \\0, \\\\, \\\", \\`, \\$, \\uXXXX жана \\u{X...}; `...` сабы тескери сызыктарды жазылгандай сактайт",
+		EMPTY_INTERPOLATION:   "кашаалардын ортосуна туюнтма жазыңыз же `${` текстинин өзү үчүн `\\${` жазыңыз",
+		INVALID_NUMBER_TEXT:   "сандарды 1 234,5 түрүндө жазыңыз",
+		MALFORMED_NUMBER:      "сандар мындай жазылат: 42, 1_000, 3.14, 1.5e-3, 2.50d, 0xFF, 0b1010 же 0o17",
+		NON_ASCII_DIGIT:       "коддогу сандарды 0-9 цифралары менен жазыңыз",
+		UNKNOWN_ROUNDING_MODE: "ыкмалар: half_even, half_up, half_down, up, down, ceiling, floor",
+		INVALID_DECIMAL:       "ондук сандын үтүрдөн кийин эң көп %[2]d цифрасы, ал даража көрсөткүчү эң көп %[2]d боло алат",
+	},
+	"qzl": {
+		INVALID_ASSIGN_TARGET: "`=` belgısınıñ sol jağynda tek ainymaly atauy tūra alady",
+		UNKNOWN_DIALECT:       "qoljetımdı dialektıler: %[2]s",
+		IDENTIFIER_NOT_FOUND:  "aldymen ony `bolsyn` arqyly jariialañyz",
+		UNDECLARED_ASSIGNMENT: "män bermes būryn ony `bolsyn` arqyly jariialañyz",
+		UNUSABLE_HASH_KEY:     "hesh kıltı tek jol, bütın san nemese logikalyq män bola alady",
+		UNTERMINATED_COMMENT:  "ony `*/` arqyly jabyñyz; tüsınıktemeler kırıstırıledı, sondyqtan är `/*` öz `*/` belgısın qajet etedı",
+		UNTERMINATED_STRING:   "\"...\" joly bastalğan jolynda aiaqtalady; bırneşe jolğa sozylatyn mätın üşın \"\"\"...\"\"\" qoldanyñyz",
+		INVALID_ESCAPE:        "jaramdy tızbekter: \\n, \\t, \\r, \\0, \\\\, \\\", \\`, \\$, \\uXXXX jäne \\u{X...}; `...` jolynda kerı qiğaş syzyqtar jazylğandai qalady",
+		EMPTY_INTERPOLATION:   "jaqşalardyñ arasyna örnek jazyñyz nemese `${` mätınınıñ özı üşın `\\${` jazyñyz",
+		INVALID_NUMBER_TEXT:   "sandardy 1 234,5 türınde jazyñyz",
+		MALFORMED_NUMBER:      "sandar bylai jazylady: 42, 1_000, 3.14, 1.5e-3, 2.50d, 0xFF, 0b1010 nemese 0o17",
+		NON_ASCII_DIGIT:       "kodtağy sandardy 0-9 sifrlarymen jazyñyz",
+		UNKNOWN_ROUNDING_MODE: "täsılder: half_even, half_up, half_down, up, down, ceiling, floor",
+		INVALID_DECIMAL:       "ondyq sannyñ ütırden keiın eñ köbı %[2]d sifry, al däreje körsetkışı eñ köbı %[2]d bola alady",
+	},
+}
+
+// Labels holds the fixed words used when rendering a diagnostic.
+type Labels struct {
+	Error    string
+	Warning  string
+	Note     string
+	Location string // format with line and column
+	Hint     string
+}
+
+// LabelsByDialect holds the rendering labels per dialect.
+var LabelsByDialect = map[string]Labels{
+	"eng": {Error: "error", Warning: "warning", Note: "note", Location: "line %d, column %d", Hint: "hint"},
+	"qzq": {Error: "қате", Warning: "ескерту", Note: "ескертпе", Location: "%d-жол, %d-баған", Hint: "кеңес"},
+	"trk": {Error: "hata", Warning: "uyarı", Note: "not", Location: "satır %d, sütun %d", Hint: "ipucu"},
+	"rus": {Error: "ошибка", Warning: "предупреждение", Note: "примечание", Location: "строка %d, столбец %d", Hint: "подсказка"},
+	"uzb": {Error: "xato", Warning: "ogohlantirish", Note: "eslatma", Location: "%d-qator, %d-ustun", Hint: "maslahat"},
+	"kgz": {Error: "ката", Warning: "эскертүү", Note: "эскертме", Location: "%d-сап, %d-тилке", Hint: "кеңеш"},
+	"qzl": {Error: "qate", Warning: "eskertu", Note: "eskertpe", Location: "%d-jol, %d-bağan", Hint: "keñes"},
+}
+
+// Message renders the template for code in dialect, falling back to
+// DEFAULT_DIALECT when the dialect or the code has no translation.
+func Message(dialect string, code Code, args ...interface{}) string {
+	return fmt.Sprintf(lookup(Messages, dialect, code), args...)
+}
+
+// Hint renders the hint for code in dialect, or "" when there is none.
+func Hint(dialect string, code Code, args ...interface{}) string {
+	template := lookup(Hints, dialect, code)
+	if !strings.Contains(template, "%") {
+		return template
+	}
+	return fmt.Sprintf(template, args...)
+}
+
+func lookup(catalog map[string]map[Code]string, dialect string, code Code) string {
+	if template, ok := catalog[dialect][code]; ok {
+		return template
+	}
+	return catalog[DEFAULT_DIALECT][code]
+}
+
+func labels(dialect string) Labels {
+	if l, ok := LabelsByDialect[dialect]; ok {
+		return l
+	}
+	return LabelsByDialect[DEFAULT_DIALECT]
+}
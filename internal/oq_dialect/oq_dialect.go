@@ -0,0 +1,308 @@
+package oq_dialect
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"unicode"
+
+	"github.com/adamerikoff/oq/internal/oq_diagnostic"
+	"github.com/adamerikoff/oq/internal/oq_evaluator"
+	"github.com/adamerikoff/oq/internal/oq_token"
+)
+
+// SEARCH_PATH_ENV lists extra directories to load dialect files from,
+// separated the same way as PATH.
+const SEARCH_PATH_ENV = "OQ_DIALECT_PATH"
+
+// DEFAULT_DIRECTORY is searched, relative to the working directory, after the
+// directories in SEARCH_PATH_ENV.
+const DEFAULT_DIRECTORY = "dialects"
+
+// Definition is the content of a dialect file. Every map is keyed by the
+// canonical (eng) word or by a diagnostic code:
+//
+//	{
+//	  "name": "deu",
+//	  "keywords": {"let": "sei", "if": "wenn"},
+//	  "booleans": {"true": "wahr", "false": "falsch"},
+//	  "builtins": {"len": "länge"},
+//	  "messages": {"R003": "Bezeichner nicht gefunden: %s"}
+//	}
+type Definition struct {
+	Name     string            `json:"name"`
+	Keywords map[string]string `json:"keywords"`
+	Booleans map[string]string `json:"booleans"`
+	Builtins map[string]string `json:"builtins"`
+	Messages map[string]string `json:"messages"`
+	Hints    map[string]string `json:"hints"`
+	Labels   map[string]string `json:"labels"` // error, warning, note, location, hint
+
+	source string // file the definition was read from, for error messages
+}
+
+// SearchPath returns the directories dialect files are loaded from, in order.
+func SearchPath() []string {
+	dirs := []string{}
+	for _, dir := range filepath.SplitList(os.Getenv(SEARCH_PATH_ENV)) {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return append(dirs, DEFAULT_DIRECTORY)
+}
+
+// loaded maps each dialect registered by Load to the file it came from.
+var loaded = map[string]string{}
+
+// Load registers every *.json dialect file found in dirs and returns the names
+// of the dialects it added. Directories that do not exist are skipped, and so
+// is a directory listed again under another spelling, as when -dialects names
+// ./dialects, which is also DEFAULT_DIRECTORY. dirs are searched in order, so
+// when two files define the same dialect the first one wins and later copies
+// are skipped.
+func Load(dirs []string) ([]string, error) {
+	names := []string{}
+	visited := map[string]bool{}
+
+	for _, dir := range dirs {
+		resolved, err := filepath.Abs(dir)
+		if err != nil {
+			resolved = filepath.Clean(dir)
+		}
+		if visited[resolved] {
+			continue
+		}
+		visited[resolved] = true
+
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return names, err
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+				continue
+			}
+
+			definition, err := ReadFile(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				return names, err
+			}
+			if _, ok := loaded[definition.Name]; ok {
+				continue
+			}
+			if err := Register(definition); err != nil {
+				return names, err
+			}
+			loaded[definition.Name] = definition.source
+			names = append(names, definition.Name)
+		}
+	}
+
+	return names, nil
+}
+
+// ReadFile parses the dialect file at path without registering it.
+func ReadFile(path string) (*Definition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	definition, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	definition.source = path
+	return definition, nil
+}
+
+// Parse decodes a dialect definition. Unknown fields are rejected so typos
+// such as "keyword" do not silently produce an empty dialect.
+func Parse(data []byte) (*Definition, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	definition := &Definition{}
+	if err := decoder.Decode(definition); err != nil {
+		return nil, err
+	}
+	return definition, nil
+}
+
+// Validate checks the definition against the dialects and builtins that are
+// already registered.
+func (d *Definition) Validate() error {
+	if !isIdentifier(d.Name) {
+		return d.errorf("dialect name %q is not a valid identifier", d.Name)
+	}
+	if _, ok := oq_token.AllDialectsMap[d.Name]; ok {
+		return d.errorf("dialect %q is already defined", d.Name)
+	}
+
+	// spellings remembers which canonical word each spelling was given to.
+	spellings := map[string]string{}
+
+	for _, canonical := range sortedKeys(d.Keywords) {
+		info, ok := oq_token.EngKeywords[canonical]
+		if !ok || info.Type == oq_token.TRUE || info.Type == oq_token.FALSE {
+			return d.errorf("unknown keyword %q", canonical)
+		}
+		if err := d.claim(spellings, canonical, d.Keywords[canonical]); err != nil {
+			return err
+		}
+	}
+
+	for _, canonical := range sortedKeys(d.Booleans) {
+		if canonical != "true" && canonical != "false" {
+			return d.errorf("unknown boolean %q, expected \"true\" or \"false\"", canonical)
+		}
+		if err := d.claim(spellings, canonical, d.Booleans[canonical]); err != nil {
+			return err
+		}
+	}
+
+	aliases := map[string]string{}
+
+	for _, name := range sortedKeys(d.Builtins) {
+		alias := d.Builtins[name]
+		if !isIdentifier(alias) {
+			return d.errorf("builtin alias %q is not a valid identifier", alias)
+		}
+		if keyword, ok := spellings[alias]; ok {
+			return d.errorf("builtin alias %q for %q is already the keyword %q", alias, name, keyword)
+		}
+		if other, ok := aliases[alias]; ok {
+			return d.errorf("builtin alias %q is used for both %q and %q", alias, other, name)
+		}
+		aliases[alias] = name
+		if err := oq_evaluator.CheckBuiltinAlias(d.Name, name, alias); err != nil {
+			return d.errorf("%v", err)
+		}
+	}
+
+	for _, catalog := range []map[string]string{d.Messages, d.Hints} {
+		for _, code := range sortedKeys(catalog) {
+			if _, ok := oq_diagnostic.Messages[oq_diagnostic.DEFAULT_DIALECT][oq_diagnostic.Code(code)]; !ok {
+				return d.errorf("unknown diagnostic code %q", code)
+			}
+		}
+	}
+
+	for _, label := range sortedKeys(d.Labels) {
+		switch label {
+		case "error", "warning", "note", "location", "hint":
+		default:
+			return d.errorf("unknown label %q", label)
+		}
+	}
+
+	return nil
+}
+
+// Register validates the definition and, only if it is valid, makes its
+// keywords, builtin aliases and messages available under its name.
+func Register(d *Definition) error {
+	if err := d.Validate(); err != nil {
+		return err
+	}
+
+	keywords := map[string]oq_token.KeywordInfo{}
+	for canonical, spelling := range d.Keywords {
+		keywords[spelling] = oq_token.EngKeywords[canonical]
+	}
+	for canonical, spelling := range d.Booleans {
+		keywords[spelling] = oq_token.EngKeywords[canonical]
+	}
+	oq_token.RegisterDialect(d.Name, keywords)
+
+	for name, alias := range d.Builtins {
+		if err := oq_evaluator.RegisterBuiltinAlias(d.Name, name, alias); err != nil {
+			return d.errorf("%v", err)
+		}
+	}
+
+	oq_diagnostic.Messages[d.Name] = codeMap(d.Messages)
+	oq_diagnostic.Hints[d.Name] = codeMap(d.Hints)
+	oq_diagnostic.LabelsByDialect[d.Name] = d.labels()
+
+	return nil
+}
+
+// claim records spelling as the word for canonical, failing when another
+// keyword already uses it.
+func (d *Definition) claim(spellings map[string]string, canonical, spelling string) error {
+	if !isIdentifier(spelling) {
+		return d.errorf("spelling %q of keyword %q is not a valid identifier", spelling, canonical)
+	}
+	if other, ok := spellings[spelling]; ok {
+		return d.errorf("keyword %q is used for both %q and %q", spelling, other, canonical)
+	}
+	spellings[spelling] = canonical
+	return nil
+}
+
+// labels fills the labels missing from the file with the default ones.
+func (d *Definition) labels() oq_diagnostic.Labels {
+	labels := oq_diagnostic.LabelsByDialect[oq_diagnostic.DEFAULT_DIALECT]
+	for label, text := range d.Labels {
+		switch label {
+		case "error":
+			labels.Error = text
+		case "warning":
+			labels.Warning = text
+		case "note":
+			labels.Note = text
+		case "location":
+			labels.Location = text
+		case "hint":
+			labels.Hint = text
+		}
+	}
+	return labels
+}
+
+func (d *Definition) errorf(format string, a ...interface{}) error {
+	source := d.source
+	if source == "" {
+		source = "dialect " + d.Name
+	}
+	return fmt.Errorf("%s: %s", source, fmt.Sprintf(format, a...))
+}
+
+func codeMap(catalog map[string]string) map[oq_diagnostic.Code]string {
+	result := map[oq_diagnostic.Code]string{}
+	for code, template := range catalog {
+		result[oq_diagnostic.Code(code)] = template
+	}
+	return result
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// isIdentifier mirrors the lexer: a word made only of letters and underscores.
+func isIdentifier(word string) bool {
+	if word == "" {
+		return false
+	}
+	for _, character := range word {
+		if !unicode.IsLetter(character) && character != '_' {
+			return false
+		}
+	}
+	return true
+}
@@ -1,9 +1,12 @@
 package oq_evaluator
 
 import (
-	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/adamerikoff/oq/internal/oq_ast"
+	"github.com/adamerikoff/oq/internal/oq_diagnostic"
 )
 
 var (
@@ -12,7 +15,22 @@ var (
 	FALSE = &Boolean{Value: false}
 )
 
+// Eval evaluates node in env. Errors produced while evaluating node are
+// tagged with the span of the innermost node they came from and worded in
+// the dialect that was active there.
 func Eval(node oq_ast.Node, env *Environment) Object {
+	result := evalNode(node, env)
+
+	if err, ok := result.(*Error); ok && err.Position.Line == 0 {
+		err.Position = node.Pos()
+		err.End = node.EndPos()
+		err.Localize(node.Dialect())
+	}
+
+	return result
+}
+
+func evalNode(node oq_ast.Node, env *Environment) Object {
 	switch node := node.(type) {
 	// Statements
 	case *oq_ast.LetStatement:
@@ -21,6 +39,15 @@ func Eval(node oq_ast.Node, env *Environment) Object {
 			return val
 		}
 		env.Set(node.Name.Value, val)
+	case *oq_ast.AssignExpression:
+		val := Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
+		if _, ok := env.Assign(node.Name.Value, val); !ok {
+			return newError(oq_diagnostic.UNDECLARED_ASSIGNMENT, node.Name.Value)
+		}
+		return val
 	case *oq_ast.Identifier:
 		return evalIdentifier(node, env)
 	case *oq_ast.Program:
@@ -38,11 +65,23 @@ func Eval(node oq_ast.Node, env *Environment) Object {
 	// Expressions
 	case *oq_ast.StringLiteral:
 		return &String{Value: node.Value}
+	case *oq_ast.InterpolatedString:
+		return evalInterpolatedString(node, env)
 	case *oq_ast.IntegerLiteral:
+		if node.Big != nil {
+			return &BigInt{Value: node.Big}
+		}
 		return &Integer{Value: node.Value}
+	case *oq_ast.DecimalLiteral:
+		// The parser has already rejected literals parseDecimal cannot read.
+		decimal, _ := parseDecimal(node.Value)
+		return decimal
 	case *oq_ast.FloatLiteral: // Add this case
 		return &Float{Value: node.Value}
 	case *oq_ast.InfixExpression:
+		if node.Operator == "and" || node.Operator == "or" {
+			return evalLogicalExpression(node, env)
+		}
 		left := Eval(node.Left, env)
 		if isError(left) {
 			return left
@@ -51,11 +90,15 @@ func Eval(node oq_ast.Node, env *Environment) Object {
 		if isError(right) {
 			return right
 		}
-		return evalInfixExpression(node.Operator, left, right)
+		return evalInfixExpression(node.Operator, left, right, node.Dialect())
 	case *oq_ast.BlockStatement:
 		return evalBlockStatement(node, env)
 	case *oq_ast.IfExpression:
 		return evalIfExpression(node, env)
+	case *oq_ast.WhileStatement:
+		return evalWhileStatement(node, env)
+	case *oq_ast.ForStatement:
+		return evalForStatement(node, env)
 	case *oq_ast.ReturnStatement:
 		val := Eval(node.ReturnValue, env)
 		if isError(val) {
@@ -75,7 +118,27 @@ func Eval(node oq_ast.Node, env *Environment) Object {
 		if len(args) == 1 && isError(args[0]) {
 			return args[0]
 		}
-		return applyFunction(function, args)
+		return applyFunction(function, args, node.Dialect())
+	case *oq_ast.ArrayLiteral:
+		elements := evalExpressions(node.Elements, env)
+		if len(elements) == 1 && isError(elements[0]) {
+			return elements[0]
+		}
+		return &Array{Elements: elements}
+	case *oq_ast.IndexExpression:
+		left := Eval(node.Left, env)
+		if isError(left) {
+			return left
+		}
+		index := Eval(node.Index, env)
+		if isError(index) {
+			return index
+		}
+		return evalIndexExpression(left, index)
+	case *oq_ast.SliceExpression:
+		return evalSliceExpression(node, env)
+	case *oq_ast.HashLiteral:
+		return evalHashLiteral(node, env)
 	}
 
 	return nil
@@ -109,7 +172,7 @@ func evalPrefixExpression(operator string, right Object) Object {
 	case "-":
 		return evalMinusPrefixOperatorExpression(right)
 	default:
-		return newError("unknown operator: %s%s", operator, right.Type())
+		return newError(oq_diagnostic.UNKNOWN_PREFIX_OPERATOR, operator, right.Type())
 	}
 }
 
@@ -127,44 +190,50 @@ func evalBangOperatorExpression(right Object) Object {
 }
 
 func evalMinusPrefixOperatorExpression(right Object) Object {
-	if right.Type() == INTEGER_OBJ {
-		value := right.(*Integer).Value
-
-		return &Integer{Value: -value}
+	if isNumber(right) {
+		return negateNumber(right)
 	}
 
-	if right.Type() == FLOAT_OBJ {
-		value := right.(*Float).Value
-
-		return &Float{Value: -value}
-	}
-
-	return newError("unknown operator: -%s", right.Type())
+	return newError(oq_diagnostic.UNKNOWN_PREFIX_OPERATOR, "-", right.Type())
 }
 
-func evalInfixExpression(operator string, left, right Object) Object {
-	// Case 1: Both are Integers
-	if left.Type() == INTEGER_OBJ && right.Type() == INTEGER_OBJ {
-		return evalIntegerInfixExpression(operator, left, right)
+func evalInfixExpression(operator string, left, right Object, dialect string) Object {
+	if isNumber(left) && isNumber(right) {
+		return evalNumericInfixExpression(operator, left, right)
 	}
-	// Case 2: Both are Floats
-	if left.Type() == FLOAT_OBJ && right.Type() == FLOAT_OBJ {
-		return evalFloatInfixExpression(operator, left, right)
-	}
-	// Case 3: Mixed types (Integer and Float) - promoted to float
-	if (left.Type() == INTEGER_OBJ && right.Type() == FLOAT_OBJ) ||
-		(left.Type() == FLOAT_OBJ && right.Type() == INTEGER_OBJ) {
-		return evalMixedNumericInfixExpression(operator, left, right)
-	}
-	// Case 4: Both are Booleans (for == and !=)
+	// Booleans only compare for == and !=
 	if left.Type() == BOOLEAN_OBJ && right.Type() == BOOLEAN_OBJ {
 		return evalBooleanInfixExpression(operator, left, right)
 	}
 	if left.Type() == STRING_OBJ && right.Type() == STRING_OBJ {
-		return evalStringInfixExpression(operator, left, right)
+		return evalStringInfixExpression(operator, left, right, dialect)
 	}
 	// Default: Handle other types or invalid combinations
-	return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
+	return newError(oq_diagnostic.TYPE_MISMATCH, left.Type(), operator, right.Type())
+}
+
+// evalLogicalExpression evaluates `and` and `or` lazily: the right operand is
+// only evaluated when the left one does not already decide the result.
+// Operands are judged with isTruthy and the result is always a Boolean.
+func evalLogicalExpression(node *oq_ast.InfixExpression, env *Environment) Object {
+	left := Eval(node.Left, env)
+	if isError(left) {
+		return left
+	}
+
+	if node.Operator == "and" && !isTruthy(left) {
+		return FALSE
+	}
+	if node.Operator == "or" && isTruthy(left) {
+		return TRUE
+	}
+
+	right := Eval(node.Right, env)
+	if isError(right) {
+		return right
+	}
+
+	return nativeBoolToBooleanObject(isTruthy(right))
 }
 
 func evalBooleanInfixExpression(operator string, left, right Object) Object {
@@ -178,7 +247,7 @@ func evalBooleanInfixExpression(operator string, left, right Object) Object {
 		return nativeBoolToBooleanObject(leftVal != rightVal)
 	default:
 		// Boolean objects only support == and != for now
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newError(oq_diagnostic.UNKNOWN_OPERATOR, left.Type(), operator, right.Type())
 	}
 }
 
@@ -188,24 +257,43 @@ func evalIntegerInfixExpression(operator string, left, right Object) Object {
 
 	switch operator {
 	case "+":
-		return &Integer{Value: leftVal + rightVal}
+		if sum := leftVal + rightVal; (sum > leftVal) == (rightVal > 0) {
+			return &Integer{Value: sum}
+		}
 	case "-":
-		return &Integer{Value: leftVal - rightVal}
+		if difference := leftVal - rightVal; (difference < leftVal) == (rightVal > 0) {
+			return &Integer{Value: difference}
+		}
 	case "*":
-		return &Integer{Value: leftVal * rightVal}
+		product := leftVal * rightVal
+		if leftVal == 0 || (product/leftVal == rightVal && !(leftVal == -1 && rightVal == math.MinInt64)) {
+			return &Integer{Value: product}
+		}
 	case "/":
-		return &Integer{Value: leftVal / rightVal}
+		if rightVal == 0 {
+			return newError(oq_diagnostic.DIVISION_BY_ZERO)
+		}
+		if leftVal != math.MinInt64 || rightVal != -1 {
+			return &Integer{Value: leftVal / rightVal}
+		}
 	case "<":
 		return nativeBoolToBooleanObject(leftVal < rightVal)
 	case ">":
 		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal <= rightVal)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal >= rightVal)
 	case "==":
 		return nativeBoolToBooleanObject(leftVal == rightVal)
 	case "!=":
 		return nativeBoolToBooleanObject(leftVal != rightVal)
 	default:
-		return NULL
+		return newError(oq_diagnostic.UNKNOWN_OPERATOR, left.Type(), operator, right.Type())
 	}
+
+	// The result overflows an int64.
+	return evalBigIntInfixExpression(operator, left, right)
 }
 
 func evalFloatInfixExpression(operator string, left, right Object) Object {
@@ -225,50 +313,130 @@ func evalFloatInfixExpression(operator string, left, right Object) Object {
 		return nativeBoolToBooleanObject(leftVal < rightVal)
 	case ">":
 		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal <= rightVal)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal >= rightVal)
 	case "==":
 		return nativeBoolToBooleanObject(leftVal == rightVal)
 	case "!=":
 		return nativeBoolToBooleanObject(leftVal != rightVal)
 	default:
-		return NULL
+		return newError(oq_diagnostic.UNKNOWN_OPERATOR, left.Type(), operator, right.Type())
 	}
 }
 
-// evalMixedNumericInfixExpression promotes integers to floats for mixed operations
-func evalMixedNumericInfixExpression(operator string, left, right Object) Object {
-	var leftFloatVal float64
-	var rightFloatVal float64
+func evalIfExpression(ie *oq_ast.IfExpression, env *Environment) Object {
+	for _, branch := range ie.Branches {
+		condition := Eval(branch.Condition, env)
+
+		if isError(condition) {
+			return condition
+		}
 
-	if left.Type() == INTEGER_OBJ {
-		leftFloatVal = float64(left.(*Integer).Value)
-	} else {
-		leftFloatVal = left.(*Float).Value
+		if isTruthy(condition) {
+			return Eval(branch.Consequence, env)
+		}
 	}
 
-	if right.Type() == INTEGER_OBJ {
-		rightFloatVal = float64(right.(*Integer).Value)
-	} else {
-		rightFloatVal = right.(*Float).Value
+	if ie.Alternative != nil {
+		return Eval(ie.Alternative, env)
 	}
 
-	// Now perform the operation as if both were floats
-	return evalFloatInfixExpression(operator, &Float{Value: leftFloatVal}, &Float{Value: rightFloatVal})
+	return NULL
 }
 
-func evalIfExpression(ie *oq_ast.IfExpression, env *Environment) Object {
-	condition := Eval(ie.Condition, env)
+// evalWhileStatement re-evaluates the body for as long as the condition is truthy.
+// A return or an error inside the body stops the loop and is passed up unchanged,
+// so the enclosing block or function can unwrap it.
+func evalWhileStatement(ws *oq_ast.WhileStatement, env *Environment) Object {
+	for {
+		condition := Eval(ws.Condition, env)
+		if isError(condition) {
+			return condition
+		}
 
-	if isError(condition) {
-		return condition
+		if !isTruthy(condition) {
+			return NULL
+		}
+
+		result := Eval(ws.Body, env)
+		if result != nil {
+			rt := result.Type()
+			if rt == RETURN_VALUE_OBJ || rt == ERROR_OBJ {
+				return result
+			}
+		}
 	}
+}
 
-	if isTruthy(condition) {
-		return Eval(ie.Consequence, env)
-	} else if ie.Alternative != nil {
-		return Eval(ie.Alternative, env)
-	} else {
-		return NULL
+// evalForStatement binds the loop variable to each element produced by the
+// iterable, evaluating the body in a fresh scope per iteration. An Integer n
+// counts from 0 up to n-1, an Array yields its elements in order and a Hash
+// yields its keys in insertion order.
+func evalForStatement(fs *oq_ast.ForStatement, env *Environment) Object {
+	iterable := Eval(fs.Iterable, env)
+	if isError(iterable) {
+		return iterable
+	}
+
+	switch iterable := iterable.(type) {
+	case *Integer:
+		for i := int64(0); i < iterable.Value; i++ {
+			result := evalForIteration(fs, env, &Integer{Value: i})
+			if result != nil {
+				return result
+			}
+		}
+	case *Array:
+		for _, element := range iterable.Elements {
+			result := evalForIteration(fs, env, element)
+			if result != nil {
+				return result
+			}
+		}
+	case *String:
+		for _, character := range iterable.Value {
+			result := evalForIteration(fs, env, &String{Value: string(character)})
+			if result != nil {
+				return result
+			}
+		}
+	case *Hash:
+		for _, key := range iterable.Keys {
+			result := evalForIteration(fs, env, iterable.Pairs[key].Key)
+			if result != nil {
+				return result
+			}
+		}
+	case *BigInt:
+		// A count beyond int64 could never finish; a negative one runs no
+		// passes, the same as a negative Integer.
+		if iterable.Value.Sign() > 0 {
+			return newError(oq_diagnostic.COUNT_TOO_LARGE, iterable.Inspect())
+		}
+	default:
+		return newError(oq_diagnostic.NOT_ITERABLE, iterable.Type())
+	}
+
+	return NULL
+}
+
+// evalForIteration runs a single pass of the loop body. It returns a non-nil
+// object only when the loop has to stop early because of a return or an error.
+func evalForIteration(fs *oq_ast.ForStatement, env *Environment, element Object) Object {
+	iterationEnv := NewEnclosedEnvironment(env)
+	iterationEnv.Set(fs.Variable.Value, element)
+
+	result := Eval(fs.Body, iterationEnv)
+	if result != nil {
+		rt := result.Type()
+		if rt == RETURN_VALUE_OBJ || rt == ERROR_OBJ {
+			return result
+		}
 	}
+
+	return nil
 }
 
 func isTruthy(obj Object) bool {
@@ -318,8 +486,13 @@ func evalBlockStatement(block *oq_ast.BlockStatement, env *Environment) Object {
 	return result
 }
 
-func newError(format string, a ...interface{}) *Error {
-	return &Error{Message: fmt.Sprintf(format, a...)}
+func newError(code oq_diagnostic.Code, args ...interface{}) *Error {
+	return &Error{
+		Code:    code,
+		Args:    args,
+		Message: oq_diagnostic.Message(oq_diagnostic.DEFAULT_DIALECT, code, args...),
+		Dialect: oq_diagnostic.DEFAULT_DIALECT,
+	}
 }
 
 func isError(obj Object) bool {
@@ -334,23 +507,23 @@ func evalIdentifier(node *oq_ast.Identifier, env *Environment) Object {
 		return val
 	}
 
-	if builtin, ok := builtins[node.Value]; ok {
+	if builtin, ok := lookupBuiltin(node.Value, node.Dialect(), env.scopedBuiltins); ok {
 		return builtin
 	}
 
-	return newError("identifier not found: " + node.Value)
+	return newError(oq_diagnostic.IDENTIFIER_NOT_FOUND, node.Value)
 }
 
-func applyFunction(fn Object, args []Object) Object {
+func applyFunction(fn Object, args []Object, dialect string) Object {
 	switch fn := fn.(type) {
 	case *Function:
 		extendedEnv := extendFunctionEnv(fn, args)
 		evaluated := Eval(fn.Body, extendedEnv)
 		return unwrapReturnValue(evaluated)
 	case *Builtin:
-		return fn.Fn(args...)
+		return fn.Call(dialect, args...)
 	default:
-		return newError("not a function: %s", fn.Type())
+		return newError(oq_diagnostic.NOT_A_FUNCTION, fn.Type())
 	}
 }
 
@@ -369,14 +542,199 @@ func unwrapReturnValue(obj Object) Object {
 	return obj
 }
 
-func evalStringInfixExpression(operator string, left, right Object) Object {
-	if operator != "+" {
-		return newError("unknown operator: %s %s %s",
-			left.Type(), operator, right.Type())
-	}
-
+// evalStringInfixExpression concatenates and compares strings. Equality is
+// exact, while <, >, <= and >= follow the collation of dialect.
+func evalStringInfixExpression(operator string, left, right Object, dialect string) Object {
 	leftVal := left.(*String).Value
 	rightVal := right.(*String).Value
 
-	return &String{Value: leftVal + rightVal}
+	switch operator {
+	case "+":
+		return &String{Value: leftVal + rightVal}
+	case "==":
+		return nativeBoolToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal != rightVal)
+	case "<":
+		return nativeBoolToBooleanObject(localeFor(dialect).compare(leftVal, rightVal) < 0)
+	case ">":
+		return nativeBoolToBooleanObject(localeFor(dialect).compare(leftVal, rightVal) > 0)
+	case "<=":
+		return nativeBoolToBooleanObject(localeFor(dialect).compare(leftVal, rightVal) <= 0)
+	case ">=":
+		return nativeBoolToBooleanObject(localeFor(dialect).compare(leftVal, rightVal) >= 0)
+	default:
+		return newError(oq_diagnostic.UNKNOWN_OPERATOR, left.Type(), operator, right.Type())
+	}
+}
+
+func evalIndexExpression(left, index Object) Object {
+	switch {
+	case (left.Type() == ARRAY_OBJ || left.Type() == STRING_OBJ) && index.Type() == BIGINT_OBJ:
+		// No array or string is long enough for an index beyond int64.
+		return newError(oq_diagnostic.INDEX_OUT_OF_RANGE, index.Inspect())
+	case left.Type() == ARRAY_OBJ && index.Type() == INTEGER_OBJ:
+		return evalArrayIndexExpression(left, index)
+	case left.Type() == STRING_OBJ && index.Type() == INTEGER_OBJ:
+		return evalStringIndexExpression(left, index)
+	case left.Type() == HASH_OBJ:
+		return evalHashIndexExpression(left, index)
+	default:
+		return newError(oq_diagnostic.INDEX_NOT_SUPPORTED, left.Type())
+	}
+}
+
+func evalArrayIndexExpression(array, index Object) Object {
+	arrayObject := array.(*Array)
+	idx := index.(*Integer).Value
+	max := int64(len(arrayObject.Elements) - 1)
+
+	if idx < 0 || idx > max {
+		return NULL
+	}
+
+	return arrayObject.Elements[idx]
+}
+
+// evalStringIndexExpression returns the character (code point) at index as a
+// string of its own, so `"Әлия"[0]` is "Ә" rather than its first byte.
+func evalStringIndexExpression(str, index Object) Object {
+	characters := []rune(str.(*String).Value)
+	idx := index.(*Integer).Value
+
+	if idx < 0 || idx >= int64(len(characters)) {
+		return NULL
+	}
+
+	return &String{Value: string(characters[idx])}
+}
+
+// evalSliceExpression evaluates `left[start:end]` for strings, counted in
+// characters, and arrays. Omitted bounds default to the start and the end, and
+// bounds outside the value are clamped to it, so a slice never fails on
+// length.
+func evalSliceExpression(node *oq_ast.SliceExpression, env *Environment) Object {
+	left := Eval(node.Left, env)
+	if isError(left) {
+		return left
+	}
+
+	var length int64
+	switch left := left.(type) {
+	case *String:
+		length = int64(utf8.RuneCountInString(left.Value))
+	case *Array:
+		length = int64(len(left.Elements))
+	default:
+		return newError(oq_diagnostic.INDEX_NOT_SUPPORTED, left.Type())
+	}
+
+	start, err := evalSliceBound(node.Start, env, 0, length)
+	if err != nil {
+		return err
+	}
+	end, err := evalSliceBound(node.End, env, length, length)
+	if err != nil {
+		return err
+	}
+	if start > end {
+		start = end
+	}
+
+	switch left := left.(type) {
+	case *String:
+		return &String{Value: string([]rune(left.Value)[start:end])}
+	default:
+		elements := left.(*Array).Elements[start:end]
+		return &Array{Elements: append([]Object{}, elements...)}
+	}
+}
+
+// evalSliceBound evaluates one bound of a slice, using fallback when it is
+// omitted and clamping it to [0, length].
+func evalSliceBound(bound oq_ast.Expression, env *Environment, fallback, length int64) (int64, Object) {
+	if bound == nil {
+		return fallback, nil
+	}
+
+	value := Eval(bound, env)
+	if isError(value) {
+		return 0, value
+	}
+	if large, ok := value.(*BigInt); ok {
+		if large.Value.Sign() < 0 {
+			return 0, nil
+		}
+		return length, nil
+	}
+	integer, ok := value.(*Integer)
+	if !ok {
+		return 0, newError(oq_diagnostic.INVALID_SLICE_INDEX, value.Type())
+	}
+
+	switch {
+	case integer.Value < 0:
+		return 0, nil
+	case integer.Value > length:
+		return length, nil
+	default:
+		return integer.Value, nil
+	}
+}
+
+func evalHashLiteral(node *oq_ast.HashLiteral, env *Environment) Object {
+	hash := NewHash()
+
+	for _, pair := range node.Pairs {
+		key := Eval(pair.Key, env)
+		if isError(key) {
+			return key
+		}
+
+		hashKey, ok := key.(Hashable)
+		if !ok {
+			return newError(oq_diagnostic.UNUSABLE_HASH_KEY, key.Type())
+		}
+
+		value := Eval(pair.Value, env)
+		if isError(value) {
+			return value
+		}
+
+		hash.Set(hashKey, value)
+	}
+
+	return hash
+}
+
+func evalHashIndexExpression(hash, index Object) Object {
+	hashObject := hash.(*Hash)
+
+	key, ok := index.(Hashable)
+	if !ok {
+		return newError(oq_diagnostic.UNUSABLE_HASH_KEY, index.Type())
+	}
+
+	pair, ok := hashObject.Pairs[key.HashKey()]
+	if !ok {
+		return NULL
+	}
+
+	return pair.Value
+}
+
+// evalInterpolatedString joins the parts of node, converting every embedded
+// value to text the way it is displayed, so numbers and booleans need no cast.
+func evalInterpolatedString(node *oq_ast.InterpolatedString, env *Environment) Object {
+	var out strings.Builder
+
+	for _, part := range node.Parts {
+		value := Eval(part, env)
+		if isError(value) {
+			return value
+		}
+		out.WriteString(value.Inspect())
+	}
+
+	return &String{Value: out.String()}
 }
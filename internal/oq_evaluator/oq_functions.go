@@ -1,47 +1,540 @@
 package oq_evaluator
 
-var builtins = map[string]*Builtin{
-	"len": &Builtin{
-		Fn: func(args ...Object) Object {
-			if len(args) != 1 {
-				return newError("wrong number of arguments. got=%d, want=1", len(args))
-			}
-
-			switch arg := args[0].(type) {
-			case *String:
-				return &Integer{Value: int64(len(arg.Value))}
-			default:
-				return newError("argument to `len` not supported, got %s", args[0].Type())
-			}
-		},
-	},
-	"ұзындығы": &Builtin{
-		Fn: func(args ...Object) Object {
-			if len(args) != 1 {
-				return newError("аргументтердің қате саны. алды=%d, келеді=1",
-					len(args))
-			}
-
-			switch arg := args[0].(type) {
-			case *String:
-				return &Integer{Value: int64(len(arg.Value))}
-			default:
-				return newError("`ұзындығы` аргументіне қолдау көрсетілмейді, %s алынды", args[0].Type())
-			}
-		},
-	},
-	"uzunluk": &Builtin{
-		Fn: func(args ...Object) Object {
-			if len(args) != 1 {
-				return newError("yanlış sayıda argüman. got=%d, want=1", len(args))
-			}
-
-			switch arg := args[0].(type) {
-			case *String:
-				return &Integer{Value: int64(len(arg.Value))}
-			default:
-				return newError("`uzunluk` argümanı desteklenmiyor, %s alındı", args[0].Type())
-			}
-		},
-	},
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/adamerikoff/oq/internal/oq_diagnostic"
+	"github.com/adamerikoff/oq/internal/oq_number"
+)
+
+// builtinDefinition describes a builtin once. Name is the canonical (eng)
+// name and Aliases holds the name it goes by in each other dialect. Fn gets
+// the name the builtin was called by, so error messages quote what the user
+// wrote (their wording comes from the oq_diagnostic catalog), and the dialect
+// of the call site, whose locale rules apply to text.
+type builtinDefinition struct {
+	Name    string
+	Aliases map[string]string
+	Fn      func(name, dialect string, args ...Object) Object
+}
+
+var builtinDefinitions = []*builtinDefinition{
+	{Name: "len", Fn: builtinLen, Aliases: map[string]string{
+		"qzq": "ұзындығы", "trk": "uzunluk", "rus": "длина", "uzb": "uzunlik", "kgz": "узундугу", "qzl": "ūzyndyğy",
+	}},
+	{Name: "first", Fn: builtinFirst, Aliases: map[string]string{
+		"qzq": "бірінші", "trk": "ilk", "rus": "первый", "uzb": "birinchi", "kgz": "биринчи", "qzl": "bırınşı",
+	}},
+	{Name: "last", Fn: builtinLast, Aliases: map[string]string{
+		"qzq": "соңғы", "trk": "son", "rus": "последний", "uzb": "oxirgi", "kgz": "акыркы", "qzl": "soñğy",
+	}},
+	{Name: "rest", Fn: builtinRest, Aliases: map[string]string{
+		"qzq": "қалғаны", "trk": "kalan", "rus": "остаток", "uzb": "qolgani", "kgz": "калганы", "qzl": "qalğany",
+	}},
+	{Name: "push", Fn: builtinPush, Aliases: map[string]string{
+		"qzq": "қосу", "trk": "ekle", "rus": "добавить", "uzb": "qoʻshish", "kgz": "кошуу", "qzl": "qosu",
+	}},
+	{Name: "keys", Fn: builtinKeys, Aliases: map[string]string{
+		"qzq": "кілттер", "trk": "anahtarlar", "rus": "ключи", "uzb": "kalitlar", "kgz": "ачкычтар", "qzl": "kıltter",
+	}},
+	{Name: "values", Fn: builtinValues, Aliases: map[string]string{
+		"qzq": "мәндер", "trk": "değerler", "rus": "значения", "uzb": "qiymatlar", "kgz": "маанилер", "qzl": "mänder",
+	}},
+	{Name: "has_key", Fn: builtinHasKey, Aliases: map[string]string{
+		"qzq": "кілті_бар", "trk": "anahtar_var", "rus": "есть_ключ", "uzb": "kalit_bor", "kgz": "ачкычы_бар", "qzl": "kıltı_bar",
+	}},
+	{Name: "delete", Fn: builtinDelete, Aliases: map[string]string{
+		"qzq": "жою", "trk": "sil", "rus": "удалить", "uzb": "oʻchirish", "kgz": "өчүрүү", "qzl": "joiu",
+	}},
+	{Name: "upper", Fn: builtinUpper, Aliases: map[string]string{
+		"qzq": "бас_әріп", "trk": "büyük_harf", "rus": "верхний_регистр", "uzb": "katta_harf", "kgz": "баш_тамга", "qzl": "bas_ärıp",
+	}},
+	{Name: "lower", Fn: builtinLower, Aliases: map[string]string{
+		"qzq": "кіші_әріп", "trk": "küçük_harf", "rus": "нижний_регистр", "uzb": "kichik_harf", "kgz": "кичине_тамга", "qzl": "kışı_ärıp",
+	}},
+	{Name: "title", Fn: builtinTitle, Aliases: map[string]string{
+		"qzq": "бас_әріптен", "trk": "başlık", "rus": "с_заглавной", "uzb": "bosh_harfdan", "kgz": "баш_тамгадан", "qzl": "bas_ärıpten",
+	}},
+	{Name: "sort", Fn: builtinSort, Aliases: map[string]string{
+		"qzq": "сұрыптау", "trk": "sırala", "rus": "сортировать", "uzb": "saralash", "kgz": "иреттөө", "qzl": "sūryptau",
+	}},
+	{Name: "format", Fn: builtinFormat, Aliases: map[string]string{
+		"qzq": "пішімдеу", "trk": "biçimle", "rus": "форматировать", "uzb": "formatlash", "kgz": "форматтоо", "qzl": "pışımdeu",
+	}},
+	{Name: "parse_number", Fn: builtinParseNumber, Aliases: map[string]string{
+		"qzq": "санға_айналдыру", "trk": "sayıya_çevir", "rus": "в_число", "uzb": "songa_aylantirish", "kgz": "санга_айлантыруу", "qzl": "sanğa_ainaldyru",
+	}},
+	{Name: "decimal", Fn: builtinDecimal, Aliases: map[string]string{
+		"qzq": "ондық", "trk": "ondalık", "rus": "десятичное", "uzb": "oʻnlik", "kgz": "ондук", "qzl": "ondyq",
+	}},
+	{Name: "round", Fn: builtinRound, Aliases: map[string]string{
+		"qzq": "дөңгелектеу", "trk": "yuvarla", "rus": "округлить", "uzb": "yaxlitlash", "kgz": "тегеректөө", "qzl": "döñgelekteu",
+	}},
+}
+
+// builtins maps every name of every builtin, in any dialect, to its object.
+var builtins = map[string]*Builtin{}
+
+func init() {
+	for _, definition := range builtinDefinitions {
+		registerBuiltin(definition, oq_diagnostic.DEFAULT_DIALECT, definition.Name)
+		for dialect, alias := range definition.Aliases {
+			registerBuiltin(definition, dialect, alias)
+		}
+	}
+}
+
+func registerBuiltin(definition *builtinDefinition, dialect, name string) {
+	builtins[name] = &Builtin{
+		Name:       name,
+		Dialect:    dialect,
+		definition: definition,
+	}
+}
+
+// CheckBuiltinAlias reports why alias cannot be added as the name dialect
+// gives the builtin canonically called name, or nil if it can. The same alias
+// may be shared by several dialects, but only for the same builtin.
+func CheckBuiltinAlias(dialect, name, alias string) error {
+	definition := findBuiltinDefinition(name)
+	if definition == nil {
+		return fmt.Errorf("unknown builtin %q", name)
+	}
+	if existing, ok := definition.Aliases[dialect]; ok {
+		return fmt.Errorf("builtin %q already has the alias %q in dialect %q", name, existing, dialect)
+	}
+	if existing, ok := builtins[alias]; ok && existing.definition != definition {
+		return fmt.Errorf("alias %q for %q is already the name of builtin %q", alias, name, existing.definition.Name)
+	}
+	return nil
+}
+
+// RegisterBuiltinAlias makes the builtin canonically called name callable as
+// alias, which belongs to dialect.
+func RegisterBuiltinAlias(dialect, name, alias string) error {
+	if err := CheckBuiltinAlias(dialect, name, alias); err != nil {
+		return err
+	}
+
+	definition := findBuiltinDefinition(name)
+	definition.Aliases[dialect] = alias
+	if _, ok := builtins[alias]; !ok {
+		registerBuiltin(definition, dialect, alias)
+	}
+	return nil
+}
+
+// TranslateBuiltin returns the name the builtin called name (in any dialect)
+// goes by in dialect. Dialects without an alias use the canonical name.
+func TranslateBuiltin(name, dialect string) (string, bool) {
+	builtin, ok := builtins[name]
+	if !ok {
+		return "", false
+	}
+	if alias, ok := builtin.definition.Aliases[dialect]; ok {
+		return alias, true
+	}
+	return builtin.definition.Name, true
+}
+
+func findBuiltinDefinition(name string) *builtinDefinition {
+	for _, definition := range builtinDefinitions {
+		if definition.Name == name {
+			return definition
+		}
+	}
+	return nil
+}
+
+// lookupBuiltin resolves name to a builtin. When scoped is set, only the names
+// of dialect are accepted, plus the canonical name of builtins that dialect
+// has no alias for.
+func lookupBuiltin(name, dialect string, scoped bool) (*Builtin, bool) {
+	builtin, ok := builtins[name]
+	if !ok || !scoped {
+		return builtin, ok
+	}
+
+	alias, translated := builtin.definition.Aliases[dialect]
+	if (translated && alias == name) || (!translated && name == builtin.definition.Name) {
+		return builtin, true
+	}
+	return nil, false
+}
+
+func builtinLen(name, dialect string, args ...Object) Object {
+	if len(args) != 1 {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_COUNT, len(args), 1)
+	}
+
+	switch arg := args[0].(type) {
+	case *String:
+		return &Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
+	case *Array:
+		return &Integer{Value: int64(len(arg.Elements))}
+	case *Hash:
+		return &Integer{Value: int64(len(arg.Keys))}
+	default:
+		return newError(oq_diagnostic.UNSUPPORTED_ARGUMENT, name, args[0].Type())
+	}
+}
+
+func builtinFirst(name, dialect string, args ...Object) Object {
+	if len(args) != 1 {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_COUNT, len(args), 1)
+	}
+	if args[0].Type() != ARRAY_OBJ {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_TYPE, name, ARRAY_OBJ, args[0].Type())
+	}
+
+	arr := args[0].(*Array)
+	if len(arr.Elements) > 0 {
+		return arr.Elements[0]
+	}
+	return NULL
+}
+
+func builtinLast(name, dialect string, args ...Object) Object {
+	if len(args) != 1 {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_COUNT, len(args), 1)
+	}
+	if args[0].Type() != ARRAY_OBJ {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_TYPE, name, ARRAY_OBJ, args[0].Type())
+	}
+
+	arr := args[0].(*Array)
+	length := len(arr.Elements)
+	if length > 0 {
+		return arr.Elements[length-1]
+	}
+	return NULL
+}
+
+func builtinRest(name, dialect string, args ...Object) Object {
+	if len(args) != 1 {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_COUNT, len(args), 1)
+	}
+	if args[0].Type() != ARRAY_OBJ {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_TYPE, name, ARRAY_OBJ, args[0].Type())
+	}
+
+	arr := args[0].(*Array)
+	length := len(arr.Elements)
+	if length > 0 {
+		newElements := make([]Object, length-1)
+		copy(newElements, arr.Elements[1:length])
+		return &Array{Elements: newElements}
+	}
+	return NULL
+}
+
+func builtinPush(name, dialect string, args ...Object) Object {
+	if len(args) != 2 {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_COUNT, len(args), 2)
+	}
+	if args[0].Type() != ARRAY_OBJ {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_TYPE, name, ARRAY_OBJ, args[0].Type())
+	}
+
+	arr := args[0].(*Array)
+	length := len(arr.Elements)
+
+	newElements := make([]Object, length+1)
+	copy(newElements, arr.Elements)
+	newElements[length] = args[1]
+
+	return &Array{Elements: newElements}
+}
+
+func builtinKeys(name, dialect string, args ...Object) Object {
+	if len(args) != 1 {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_COUNT, len(args), 1)
+	}
+	if args[0].Type() != HASH_OBJ {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_TYPE, name, HASH_OBJ, args[0].Type())
+	}
+
+	hash := args[0].(*Hash)
+	elements := make([]Object, 0, len(hash.Keys))
+	for _, key := range hash.Keys {
+		pair := hash.Pairs[key]
+		elements = append(elements, pair.Key)
+	}
+	return &Array{Elements: elements}
+}
+
+func builtinValues(name, dialect string, args ...Object) Object {
+	if len(args) != 1 {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_COUNT, len(args), 1)
+	}
+	if args[0].Type() != HASH_OBJ {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_TYPE, name, HASH_OBJ, args[0].Type())
+	}
+
+	hash := args[0].(*Hash)
+	elements := make([]Object, 0, len(hash.Keys))
+	for _, key := range hash.Keys {
+		pair := hash.Pairs[key]
+		elements = append(elements, pair.Value)
+	}
+	return &Array{Elements: elements}
+}
+
+func builtinHasKey(name, dialect string, args ...Object) Object {
+	if len(args) != 2 {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_COUNT, len(args), 2)
+	}
+	if args[0].Type() != HASH_OBJ {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_TYPE, name, HASH_OBJ, args[0].Type())
+	}
+
+	key, ok := args[1].(Hashable)
+	if !ok {
+		return newError(oq_diagnostic.UNUSABLE_HASH_KEY, args[1].Type())
+	}
+
+	_, ok = args[0].(*Hash).Pairs[key.HashKey()]
+	return nativeBoolToBooleanObject(ok)
+}
+
+func builtinDelete(name, dialect string, args ...Object) Object {
+	if len(args) != 2 {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_COUNT, len(args), 2)
+	}
+	if args[0].Type() != HASH_OBJ {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_TYPE, name, HASH_OBJ, args[0].Type())
+	}
+
+	key, ok := args[1].(Hashable)
+	if !ok {
+		return newError(oq_diagnostic.UNUSABLE_HASH_KEY, args[1].Type())
+	}
+
+	return deleteHashKey(args[0].(*Hash), key)
+}
+
+// deleteHashKey returns a copy of hash without key. The original hash is left
+// untouched, the same way push returns a new array.
+func deleteHashKey(hash *Hash, key Hashable) Object {
+	result := NewHash()
+	removed := key.HashKey()
+
+	for _, hashKey := range hash.Keys {
+		if hashKey == removed {
+			continue
+		}
+		pair := hash.Pairs[hashKey]
+		result.Set(pair.Key.(Hashable), pair.Value)
+	}
+
+	return result
+}
+
+func builtinUpper(name, dialect string, args ...Object) Object {
+	return mapString(name, args, func(value string) string { return localeFor(dialect).upper(value) })
+}
+
+func builtinLower(name, dialect string, args ...Object) Object {
+	return mapString(name, args, func(value string) string { return localeFor(dialect).lower(value) })
+}
+
+func builtinTitle(name, dialect string, args ...Object) Object {
+	return mapString(name, args, func(value string) string { return localeFor(dialect).title(value) })
+}
+
+// mapString applies convert to the single string argument of a builtin.
+func mapString(name string, args []Object, convert func(string) string) Object {
+	if len(args) != 1 {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_COUNT, len(args), 1)
+	}
+	str, ok := args[0].(*String)
+	if !ok {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_TYPE, name, STRING_OBJ, args[0].Type())
+	}
+
+	return &String{Value: convert(str.Value)}
+}
+
+// builtinSort returns a sorted copy of an array of strings, ordered by the
+// collation of the calling dialect, or of numbers.
+func builtinSort(name, dialect string, args ...Object) Object {
+	if len(args) != 1 {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_COUNT, len(args), 1)
+	}
+	if args[0].Type() != ARRAY_OBJ {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_TYPE, name, ARRAY_OBJ, args[0].Type())
+	}
+
+	elements := append([]Object{}, args[0].(*Array).Elements...)
+	if len(elements) == 0 {
+		return &Array{Elements: elements}
+	}
+
+	// Strings and numbers sort, but not together.
+	kind := sortKind(elements[0])
+	for _, element := range elements {
+		if sortKind(element) == "" || sortKind(element) != kind {
+			return newError(oq_diagnostic.UNSORTABLE_ELEMENT, name, element.Type())
+		}
+	}
+
+	less := func(a, b Object) bool { return compareNumbers(a, b) < 0 }
+	if kind == STRING_OBJ {
+		locale := localeFor(dialect)
+		less = func(a, b Object) bool { return locale.compare(a.(*String).Value, b.(*String).Value) < 0 }
+	}
+
+	sort.SliceStable(elements, func(i, j int) bool { return less(elements[i], elements[j]) })
+	return &Array{Elements: elements}
+}
+
+// sortKind groups the types sort can order together: STRING_OBJ for strings,
+// INTEGER_OBJ for any number, and "" for everything else.
+func sortKind(element Object) ObjectType {
+	switch element.(type) {
+	case *String:
+		return STRING_OBJ
+	case *Integer, *BigInt, *Decimal, *Float:
+		return INTEGER_OBJ
+	default:
+		return ""
+	}
+}
+
+// builtinFormat writes a number as text the way the calling dialect does:
+// format(1234.5) is "1,234.5" in English and "1 234,5" in Kazakh. An optional
+// second argument fixes the number of decimals; a negative count means none.
+func builtinFormat(name, dialect string, args ...Object) Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_COUNT, len(args), "1-2")
+	}
+	if !isNumber(args[0]) {
+		return newError(oq_diagnostic.NOT_A_NUMBER, name, args[0].Type())
+	}
+
+	decimals := -1
+	if len(args) == 2 {
+		count, err := placesArgument(name, args[1])
+		if err != nil {
+			return err
+		}
+		decimals = max(count, 0)
+	}
+
+	return &String{Value: localeFor(dialect).formatNumber(args[0], decimals)}
+}
+
+// builtinParseNumber reads text written the way the calling dialect writes
+// numbers back into an Integer or a Float.
+func builtinParseNumber(name, dialect string, args ...Object) Object {
+	if len(args) != 1 {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_COUNT, len(args), 1)
+	}
+	str, ok := args[0].(*String)
+	if !ok {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_TYPE, name, STRING_OBJ, args[0].Type())
+	}
+
+	number, ok := localeFor(dialect).parseNumber(str.Value)
+	if !ok {
+		return newError(oq_diagnostic.INVALID_NUMBER_TEXT, name, str.Value)
+	}
+	return number
+}
+
+// builtinDecimal converts a number, or text written the way the calling
+// dialect writes numbers, to an exact Decimal. A Float converts to the
+// decimal its shortest form spells, so decimal(0.1) is 0.1d.
+func builtinDecimal(name, dialect string, args ...Object) Object {
+	if len(args) != 1 {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_COUNT, len(args), 1)
+	}
+
+	if str, ok := args[0].(*String); ok {
+		plain, ok := localeFor(dialect).plainNumber(str.Value)
+		if !ok {
+			return newError(oq_diagnostic.INVALID_NUMBER_TEXT, name, str.Value)
+		}
+		decimal, ok := parseDecimal(plain)
+		if !ok {
+			return newError(oq_diagnostic.INVALID_NUMBER_TEXT, name, str.Value)
+		}
+		return decimal
+	}
+
+	if !isNumber(args[0]) {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_TYPE, name, DECIMAL_OBJ, args[0].Type())
+	}
+	decimal, ok := toDecimal(args[0])
+	if !ok {
+		return newError(oq_diagnostic.INVALID_NUMBER_TEXT, name, args[0].Inspect())
+	}
+	return decimal
+}
+
+// builtinRound rounds a number to a count of digits after the point, 0 by
+// default, and keeps its type. The optional third argument names the
+// rounding mode; half_even is the default. A negative count rounds to tens,
+// hundreds and so on: round(1250, -2) is 1200.
+func builtinRound(name, dialect string, args ...Object) Object {
+	if len(args) < 1 || len(args) > 3 {
+		return newError(oq_diagnostic.WRONG_ARGUMENT_COUNT, len(args), "1-3")
+	}
+	if !isNumber(args[0]) {
+		return newError(oq_diagnostic.NOT_A_NUMBER, name, args[0].Type())
+	}
+
+	places := 0
+	if len(args) >= 2 {
+		count, err := placesArgument(name, args[1])
+		if err != nil {
+			return err
+		}
+		places = count
+	}
+
+	mode := ROUND_HALF_EVEN
+	if len(args) == 3 {
+		str, ok := args[2].(*String)
+		if !ok {
+			return newError(oq_diagnostic.WRONG_ARGUMENT_TYPE, name, STRING_OBJ, args[2].Type())
+		}
+		if !roundingModes[str.Value] {
+			return newError(oq_diagnostic.UNKNOWN_ROUNDING_MODE, name, str.Value)
+		}
+		mode = str.Value
+	}
+
+	decimal, ok := toDecimal(args[0])
+	if !ok {
+		return args[0] // infinities and NaN stay as they are
+	}
+
+	switch args[0].(type) {
+	case *Decimal:
+		return roundDecimal(decimal, places, mode)
+	case *Float:
+		return &Float{Value: toFloat(roundDecimal(decimal, places, mode))}
+	default:
+		if places >= 0 {
+			return args[0]
+		}
+		return normalizeBigInt(roundDecimal(decimal, places, mode).Unscaled)
+	}
+}
+
+// placesArgument reads the count of digits after the point that round and
+// format take. Counts beyond MAX_DECIMAL_SCALE either way are refused, since
+// the power of ten they need would take too long to compute.
+func placesArgument(name string, arg Object) (int, Object) {
+	count, ok := arg.(*Integer)
+	if !ok {
+		return 0, newError(oq_diagnostic.WRONG_ARGUMENT_TYPE, name, INTEGER_OBJ, arg.Type())
+	}
+	if count.Value < -oq_number.MAX_DECIMAL_SCALE || count.Value > oq_number.MAX_DECIMAL_SCALE {
+		return 0, newError(oq_diagnostic.PLACES_OUT_OF_RANGE, name, count.Value, oq_number.MAX_DECIMAL_SCALE)
+	}
+	return int(count.Value), nil
 }
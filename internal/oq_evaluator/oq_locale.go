@@ -0,0 +1,348 @@
+package oq_evaluator
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// locale holds the text rules of a dialect's language: how letters change
+// case, the order of its alphabet and how numbers are written.
+type locale struct {
+	casing   unicode.SpecialCase // nil for the default Unicode mapping
+	alphabet []string            // lower-case letters in order; a letter may take several characters
+	decimal  string              // separates the fraction, as in 1.5 or 1,5
+	group    string              // separates thousands, as in 1,000 or 1 000
+}
+
+// NO_BREAK_SPACE groups thousands in most of the dialects' languages.
+const NO_BREAK_SPACE = "\u00a0"
+
+// locales maps each built-in dialect to its rules. Dialects missing here,
+// including the ones loaded from files, use defaultLocale.
+var locales = map[string]*locale{
+	"eng": {
+		alphabet: strings.Split("abcdefghijklmnopqrstuvwxyz", ""),
+		decimal:  ".", group: ",",
+	},
+	"qzq": {
+		alphabet: strings.Split("аәбвгғдеёжзийкқлмнңоөпрстуұүфхһцчшщъыіьэюя", ""),
+		decimal:  ",", group: NO_BREAK_SPACE,
+	},
+	"trk": {
+		casing:   unicode.TurkishCase,
+		alphabet: strings.Split("abcçdefgğhıijklmnoöprsştuüvyz", ""),
+		decimal:  ",", group: ".",
+	},
+	"rus": {
+		alphabet: strings.Split("абвгдеёжзийклмнопрстуфхцчшщъыьэюя", ""),
+		decimal:  ",", group: NO_BREAK_SPACE,
+	},
+	"uzb": {
+		alphabet: append(strings.Split("abdefghijklmnopqrstuvxyz", ""), "oʻ", "gʻ", "sh", "ch", "ng"),
+		decimal:  ",", group: NO_BREAK_SPACE,
+	},
+	"kgz": {
+		alphabet: strings.Split("абвгдеёжзийклмнңоөпрстуүфхцчшщъыьэюя", ""),
+		decimal:  ",", group: NO_BREAK_SPACE,
+	},
+	"qzl": {
+		casing:   unicode.TurkishCase,
+		alphabet: strings.Split("aäbdefgğhıijklmnñoöpqrsştuūüvyz", ""),
+		decimal:  ",", group: NO_BREAK_SPACE,
+	},
+}
+
+var defaultLocale = locales["eng"]
+
+func localeFor(dialect string) *locale {
+	if l, ok := locales[dialect]; ok {
+		return l
+	}
+	return defaultLocale
+}
+
+func (l *locale) upper(s string) string {
+	if l.casing != nil {
+		return strings.ToUpperSpecial(l.casing, s)
+	}
+	return strings.ToUpper(s)
+}
+
+func (l *locale) lower(s string) string {
+	if l.casing != nil {
+		return strings.ToLowerSpecial(l.casing, s)
+	}
+	return strings.ToLower(s)
+}
+
+// title upper-cases the first letter of every word and lower-cases the rest.
+func (l *locale) title(s string) string {
+	var out strings.Builder
+	inWord := false
+
+	for _, character := range s {
+		switch {
+		case !unicode.IsLetter(character):
+			out.WriteRune(character)
+			inWord = character == '\'' // as in "don't"
+		case inWord:
+			out.WriteString(l.lower(string(character)))
+		default:
+			out.WriteString(l.titleCase(character))
+			inWord = true
+		}
+	}
+
+	return out.String()
+}
+
+func (l *locale) titleCase(character rune) string {
+	if l.casing != nil {
+		return string(l.casing.ToTitle(character))
+	}
+	return string(unicode.ToTitle(character))
+}
+
+// compare orders a and b the way a dictionary of the language would: letter
+// by letter in alphabet order, ignoring case, with a lower-case letter before
+// its upper-case form when the words are otherwise equal. Characters outside
+// the alphabet sort before its letters if they are not letters themselves,
+// and after them otherwise.
+func (l *locale) compare(a, b string) int {
+	keysA, keysB := l.sortKeys(a), l.sortKeys(b)
+
+	for i := 0; i < len(keysA) && i < len(keysB); i++ {
+		if keysA[i] != keysB[i] {
+			return compareInts(keysA[i], keysB[i])
+		}
+	}
+	if len(keysA) != len(keysB) {
+		return compareInts(len(keysA), len(keysB))
+	}
+
+	// Same letters: lower case first, then plain code point order.
+	lowerA, lowerB := l.lower(a) == a, l.lower(b) == b
+	switch {
+	case lowerA && !lowerB:
+		return -1
+	case !lowerA && lowerB:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// sortKeys turns s into one weight per letter, reading the longest alphabet
+// letter that matches at each point. Alphabet letters weigh their index,
+// other letters weigh more and everything else is negative.
+func (l *locale) sortKeys(s string) []int {
+	lowered := l.lower(s)
+	keys := []int{}
+
+	for len(lowered) > 0 {
+		index, size := l.letterAt(lowered)
+		if index < 0 {
+			var character rune
+			character, size = utf8.DecodeRuneInString(lowered)
+			if unicode.IsLetter(character) {
+				index = len(l.alphabet) + int(character)
+			} else {
+				index = int(character) - unicode.MaxRune - 1
+			}
+		}
+		keys = append(keys, index)
+		lowered = lowered[size:]
+	}
+
+	return keys
+}
+
+// letterAt finds the alphabet letter s starts with, preferring the longest
+// one, and returns its index and length in bytes, or -1 if there is none.
+func (l *locale) letterAt(s string) (int, int) {
+	index, size := -1, 0
+	for i, letter := range l.alphabet {
+		if len(letter) > size && strings.HasPrefix(s, letter) {
+			index, size = i, len(letter)
+		}
+	}
+	return index, size
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Display renders obj the way Inspect does, but writes numbers, including the
+// ones inside arrays and hashes, the way the language of dialect does. Where
+// the decimal separator is a comma, elements are separated by semicolons.
+func Display(obj Object, dialect string) string {
+	l := localeFor(dialect)
+	separator := ", "
+	if l.decimal == "," {
+		separator = "; "
+	}
+
+	switch obj := obj.(type) {
+	case *Integer, *BigInt, *Decimal, *Float:
+		return l.formatNumber(obj, -1)
+	case *Array:
+		elements := []string{}
+		for _, element := range obj.Elements {
+			elements = append(elements, Display(element, dialect))
+		}
+		return "[" + strings.Join(elements, separator) + "]"
+	case *Hash:
+		pairs := []string{}
+		for _, key := range obj.Keys {
+			pair := obj.Pairs[key]
+			pairs = append(pairs, Display(pair.Key, dialect)+": "+Display(pair.Value, dialect))
+		}
+		return "{" + strings.Join(pairs, separator) + "}"
+	default:
+		return obj.Inspect()
+	}
+}
+
+// formatFloat writes value in the shortest form that reads back as the same
+// float. It always shows a fraction or an exponent, so 25.0 stays a float
+// rather than looking like the integer 25.
+func formatFloat(value float64) string {
+	magnitude := math.Abs(value)
+	if magnitude != 0 && (magnitude < 1e-6 || magnitude >= 1e21) {
+		return strconv.FormatFloat(value, 'e', -1, 64)
+	}
+
+	formatted := strconv.FormatFloat(value, 'f', -1, 64)
+	if !strings.ContainsAny(formatted, ".IN") { // Inf and NaN have no fraction
+		formatted += ".0"
+	}
+	return formatted
+}
+
+// formatNumber writes a number with the separators of l. A negative
+// decimals keeps the shortest form, or for a Decimal the digits it has;
+// otherwise exactly that many digits follow the decimal separator.
+func (l *locale) formatNumber(number Object, decimals int) string {
+	var formatted string
+	switch number := number.(type) {
+	case *Integer, *BigInt:
+		formatted = number.Inspect()
+		if decimals > 0 {
+			formatted += "." + strings.Repeat("0", decimals)
+		}
+	case *Decimal:
+		if decimals >= 0 {
+			number = roundDecimal(number, decimals, ROUND_HALF_EVEN)
+		}
+		formatted = number.Inspect()
+	case *Float:
+		if decimals >= 0 {
+			formatted = strconv.FormatFloat(number.Value, 'f', decimals, 64)
+		} else {
+			formatted = formatFloat(number.Value)
+		}
+	}
+
+	if strings.ContainsAny(formatted, "eIN") {
+		return strings.Replace(formatted, ".", l.decimal, 1)
+	}
+
+	sign := ""
+	if strings.HasPrefix(formatted, "-") {
+		sign, formatted = "-", formatted[1:]
+	}
+	whole, fraction, hasFraction := strings.Cut(formatted, ".")
+
+	var out strings.Builder
+	out.WriteString(sign)
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			out.WriteString(l.group)
+		}
+		out.WriteRune(digit)
+	}
+	if hasFraction {
+		out.WriteString(l.decimal)
+		out.WriteString(fraction)
+	}
+	return out.String()
+}
+
+// parseNumber reads a number written with the separators of l into an
+// Integer, a BigInt when it is too large for one, or a Float. See
+// plainNumber for where group separators may appear.
+func (l *locale) parseNumber(text string) (Object, bool) {
+	plain, ok := l.plainNumber(text)
+	if !ok {
+		return nil, false
+	}
+	return parsePlainNumber(plain)
+}
+
+// plainNumber rewrites a number written with the separators of l the way
+// code writes it, without groups and with a point before the fraction. A
+// group separator, or a space, is only accepted between groups of three
+// digits of the whole part: in Turkish "1.234" is 1234 and "1,5" is 1.5,
+// but "1.5" is refused rather than read as 15.
+func (l *locale) plainNumber(text string) (string, bool) {
+	plain := strings.TrimSpace(text)
+	sign := ""
+	if strings.HasPrefix(plain, "-") || strings.HasPrefix(plain, "+") {
+		sign, plain = plain[:1], plain[1:]
+	}
+
+	whole, fraction, hasFraction := strings.Cut(plain, l.decimal)
+	for _, group := range []string{NO_BREAK_SPACE, " "} {
+		whole = strings.ReplaceAll(whole, group, l.group)
+		fraction = strings.ReplaceAll(fraction, group, l.group)
+	}
+	if strings.Contains(fraction, l.group) {
+		return "", false
+	}
+
+	groups := strings.Split(whole, l.group)
+	if len(groups) > 1 {
+		for i, group := range groups {
+			if strings.IndexFunc(group, func(r rune) bool { return r < '0' || r > '9' }) >= 0 ||
+				group == "" || len(group) > 3 || (i > 0 && len(group) != 3) {
+				return "", false
+			}
+		}
+	}
+
+	plain = sign + strings.Join(groups, "")
+	if hasFraction {
+		plain += "." + fraction
+	}
+	return plain, true
+}
+
+func parsePlainNumber(text string) (Object, bool) {
+	if text == "" || strings.ContainsAny(text, "_xXoObB") {
+		return nil, false
+	}
+	if value, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return &Integer{Value: value}, true
+	}
+	if value, ok := new(big.Int).SetString(text, 10); ok {
+		return normalizeBigInt(value), true
+	}
+	if strings.ContainsAny(text, "iInN") { // no "Inf" or "NaN"
+		return nil, false
+	}
+	if value, err := strconv.ParseFloat(text, 64); err == nil {
+		return &Float{Value: value}, true
+	}
+	return nil, false
+}
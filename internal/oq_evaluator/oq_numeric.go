@@ -0,0 +1,334 @@
+package oq_evaluator
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/adamerikoff/oq/internal/oq_diagnostic"
+	"github.com/adamerikoff/oq/internal/oq_number"
+)
+
+// DECIMAL_DIVISION_PLACES is how many digits after the point a Decimal
+// quotient keeps when the division does not come out exactly.
+const DECIMAL_DIVISION_PLACES = 20
+
+// Rounding modes accepted by round. The half modes only differ on an exact
+// tie: half_even picks the even neighbour (banker's rounding), half_up goes
+// away from zero and half_down towards it.
+const (
+	ROUND_HALF_EVEN = "half_even"
+	ROUND_HALF_UP   = "half_up"
+	ROUND_HALF_DOWN = "half_down"
+	ROUND_UP        = "up"   // away from zero
+	ROUND_DOWN      = "down" // towards zero
+	ROUND_CEILING   = "ceiling"
+	ROUND_FLOOR     = "floor"
+)
+
+var roundingModes = map[string]bool{
+	ROUND_HALF_EVEN: true, ROUND_HALF_UP: true, ROUND_HALF_DOWN: true,
+	ROUND_UP: true, ROUND_DOWN: true, ROUND_CEILING: true, ROUND_FLOOR: true,
+}
+
+func isNumber(obj Object) bool {
+	switch obj.(type) {
+	case *Integer, *BigInt, *Decimal, *Float:
+		return true
+	default:
+		return false
+	}
+}
+
+// evalNumericInfixExpression brings both operands to a common type first:
+//   - two Integers stay Integers, promoted to BigInt when the result overflows;
+//   - Integers and BigInts are combined exactly, and the result goes back to
+//     Integer when it fits;
+//   - a Decimal with an Integer, a BigInt or a Decimal gives an exact Decimal;
+//   - a Float with an Integer or a BigInt gives a Float.
+//
+// A Decimal and a Float do not mix: the float is most likely inexact already,
+// so silently using it would defeat the point of the decimal. Convert it with
+// decimal() first.
+func evalNumericInfixExpression(operator string, left, right Object) Object {
+	leftType, rightType := left.Type(), right.Type()
+
+	switch {
+	case leftType == INTEGER_OBJ && rightType == INTEGER_OBJ:
+		return evalIntegerInfixExpression(operator, left, right)
+	case leftType == FLOAT_OBJ || rightType == FLOAT_OBJ:
+		if leftType == DECIMAL_OBJ || rightType == DECIMAL_OBJ {
+			return newError(oq_diagnostic.TYPE_MISMATCH, leftType, operator, rightType)
+		}
+		return evalFloatInfixExpression(operator, &Float{Value: toFloat(left)}, &Float{Value: toFloat(right)})
+	case leftType == DECIMAL_OBJ || rightType == DECIMAL_OBJ:
+		return evalDecimalInfixExpression(operator, left, right)
+	default:
+		return evalBigIntInfixExpression(operator, left, right)
+	}
+}
+
+// evalBigIntInfixExpression computes with Integers and BigInts without
+// overflowing. Division truncates towards zero, as it does for Integers.
+func evalBigIntInfixExpression(operator string, left, right Object) Object {
+	leftVal, rightVal := toBigInt(left), toBigInt(right)
+
+	switch operator {
+	case "+":
+		return normalizeBigInt(new(big.Int).Add(leftVal, rightVal))
+	case "-":
+		return normalizeBigInt(new(big.Int).Sub(leftVal, rightVal))
+	case "*":
+		return normalizeBigInt(new(big.Int).Mul(leftVal, rightVal))
+	case "/":
+		if rightVal.Sign() == 0 {
+			return newError(oq_diagnostic.DIVISION_BY_ZERO)
+		}
+		return normalizeBigInt(new(big.Int).Quo(leftVal, rightVal))
+	}
+
+	if result, ok := evalComparison(operator, leftVal.Cmp(rightVal)); ok {
+		return result
+	}
+	return newError(oq_diagnostic.UNKNOWN_OPERATOR, left.Type(), operator, right.Type())
+}
+
+// evalDecimalInfixExpression computes exactly with Decimals. Sums keep the
+// larger scale of the two operands and products the sum of both, so
+// 1.10d * 3 is 3.30d. A quotient that does not come out exactly is rounded
+// half to even at DECIMAL_DIVISION_PLACES digits.
+func evalDecimalInfixExpression(operator string, left, right Object) Object {
+	leftVal, _ := toDecimal(left)
+	rightVal, _ := toDecimal(right)
+
+	switch operator {
+	case "+":
+		a, b, scale := alignScales(leftVal, rightVal)
+		return &Decimal{Unscaled: a.Add(a, b), Scale: scale}
+	case "-":
+		a, b, scale := alignScales(leftVal, rightVal)
+		return &Decimal{Unscaled: a.Sub(a, b), Scale: scale}
+	case "*":
+		return &Decimal{Unscaled: new(big.Int).Mul(leftVal.Unscaled, rightVal.Unscaled), Scale: leftVal.Scale + rightVal.Scale}
+	case "/":
+		if rightVal.Unscaled.Sign() == 0 {
+			return newError(oq_diagnostic.DIVISION_BY_ZERO)
+		}
+		return divideDecimals(leftVal, rightVal)
+	}
+
+	a, b, _ := alignScales(leftVal, rightVal)
+	if result, ok := evalComparison(operator, a.Cmp(b)); ok {
+		return result
+	}
+	return newError(oq_diagnostic.UNKNOWN_OPERATOR, left.Type(), operator, right.Type())
+}
+
+// evalComparison turns the result of a three-way comparison into the Boolean
+// operator asks for. It reports false when operator does not compare.
+func evalComparison(operator string, comparison int) (Object, bool) {
+	switch operator {
+	case "<":
+		return nativeBoolToBooleanObject(comparison < 0), true
+	case ">":
+		return nativeBoolToBooleanObject(comparison > 0), true
+	case "<=":
+		return nativeBoolToBooleanObject(comparison <= 0), true
+	case ">=":
+		return nativeBoolToBooleanObject(comparison >= 0), true
+	case "==":
+		return nativeBoolToBooleanObject(comparison == 0), true
+	case "!=":
+		return nativeBoolToBooleanObject(comparison != 0), true
+	default:
+		return nil, false
+	}
+}
+
+// divideDecimals divides with at least DECIMAL_DIVISION_PLACES digits after
+// the point, then drops the trailing zeros that go beyond the operands' own
+// scale, so 10.00d / 4 is 2.50d rather than 2.50000000000000000000d.
+func divideDecimals(left, right *Decimal) *Decimal {
+	places := max(DECIMAL_DIVISION_PLACES, left.Scale, right.Scale)
+	numerator := new(big.Int).Mul(left.Unscaled, powerOfTen(places+right.Scale-left.Scale))
+	quotient := roundQuotient(numerator, right.Unscaled, ROUND_HALF_EVEN)
+
+	minimum := max(left.Scale, right.Scale)
+	ten, digit := big.NewInt(10), new(big.Int)
+	for places > minimum {
+		shorter, _ := new(big.Int).QuoRem(quotient, ten, digit)
+		if digit.Sign() != 0 {
+			break
+		}
+		quotient, places = shorter, places-1
+	}
+
+	return &Decimal{Unscaled: quotient, Scale: places}
+}
+
+// roundDecimal rounds d to places digits after the point using mode. A
+// negative places rounds to tens, hundreds and so on. The result has exactly
+// places digits, so round(2.5d, 2) is 2.50d.
+func roundDecimal(d *Decimal, places int, mode string) *Decimal {
+	if places >= d.Scale {
+		unscaled := new(big.Int).Mul(d.Unscaled, powerOfTen(places-d.Scale))
+		return &Decimal{Unscaled: unscaled, Scale: places}
+	}
+
+	unscaled := roundQuotient(d.Unscaled, powerOfTen(d.Scale-places), mode)
+	if places < 0 {
+		return &Decimal{Unscaled: unscaled.Mul(unscaled, powerOfTen(-places)), Scale: 0}
+	}
+	return &Decimal{Unscaled: unscaled, Scale: places}
+}
+
+// roundQuotient divides numerator by denominator and rounds the quotient to
+// an integer using mode.
+func roundQuotient(numerator, denominator *big.Int, mode string) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if remainder.Sign() == 0 {
+		return quotient
+	}
+
+	// The sign of the exact result, and how the dropped part compares to
+	// one half.
+	sign := numerator.Sign() * denominator.Sign()
+	twice := remainder.Abs(remainder).Lsh(remainder, 1)
+	half := twice.Cmp(new(big.Int).Abs(denominator))
+
+	var awayFromZero bool
+	switch mode {
+	case ROUND_UP:
+		awayFromZero = true
+	case ROUND_DOWN:
+		awayFromZero = false
+	case ROUND_CEILING:
+		awayFromZero = sign > 0
+	case ROUND_FLOOR:
+		awayFromZero = sign < 0
+	case ROUND_HALF_UP:
+		awayFromZero = half >= 0
+	case ROUND_HALF_DOWN:
+		awayFromZero = half > 0
+	default: // ROUND_HALF_EVEN
+		awayFromZero = half > 0 || (half == 0 && quotient.Bit(0) == 1)
+	}
+
+	if awayFromZero {
+		quotient.Add(quotient, big.NewInt(int64(sign)))
+	}
+	return quotient
+}
+
+// alignScales returns copies of the unscaled values of a and b brought to
+// the larger of their scales, and that scale.
+func alignScales(a, b *Decimal) (*big.Int, *big.Int, int) {
+	scale := max(a.Scale, b.Scale)
+	return new(big.Int).Mul(a.Unscaled, powerOfTen(scale-a.Scale)),
+		new(big.Int).Mul(b.Unscaled, powerOfTen(scale-b.Scale)),
+		scale
+}
+
+func powerOfTen(exponent int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+}
+
+// normalizeBigInt returns value as an Integer when it fits in one.
+func normalizeBigInt(value *big.Int) Object {
+	if value.IsInt64() {
+		return &Integer{Value: value.Int64()}
+	}
+	return &BigInt{Value: value}
+}
+
+// negateNumber implements unary minus for the numeric types. The only
+// Integer that cannot be negated in place, the smallest one, becomes a BigInt.
+func negateNumber(number Object) Object {
+	switch number := number.(type) {
+	case *Integer:
+		if number.Value == math.MinInt64 {
+			return &BigInt{Value: new(big.Int).Neg(big.NewInt(number.Value))}
+		}
+		return &Integer{Value: -number.Value}
+	case *BigInt:
+		return normalizeBigInt(new(big.Int).Neg(number.Value))
+	case *Decimal:
+		return &Decimal{Unscaled: new(big.Int).Neg(number.Unscaled), Scale: number.Scale}
+	default:
+		return &Float{Value: -number.(*Float).Value}
+	}
+}
+
+func toBigInt(number Object) *big.Int {
+	if integer, ok := number.(*Integer); ok {
+		return big.NewInt(integer.Value)
+	}
+	return number.(*BigInt).Value
+}
+
+func toFloat(number Object) float64 {
+	switch number := number.(type) {
+	case *Integer:
+		return float64(number.Value)
+	case *BigInt:
+		value, _ := new(big.Float).SetInt(number.Value).Float64()
+		return value
+	case *Decimal:
+		value, _ := new(big.Rat).SetFrac(number.Unscaled, powerOfTen(number.Scale)).Float64()
+		return value
+	default:
+		return number.(*Float).Value
+	}
+}
+
+// toDecimal converts a number to a Decimal. A Float becomes the decimal its
+// shortest form spells, so 0.1 is 0.1d rather than the binary value closest
+// to it; infinities and NaN have no decimal and report false.
+func toDecimal(number Object) (*Decimal, bool) {
+	switch number := number.(type) {
+	case *Integer:
+		return &Decimal{Unscaled: big.NewInt(number.Value)}, true
+	case *BigInt:
+		return &Decimal{Unscaled: number.Value}, true
+	case *Decimal:
+		return number, true
+	case *Float:
+		if math.IsInf(number.Value, 0) || math.IsNaN(number.Value) {
+			return nil, false
+		}
+		return parseDecimal(strconv.FormatFloat(number.Value, 'e', -1, 64))
+	default:
+		return nil, false
+	}
+}
+
+// parseDecimal reads a plain decimal number, such as -12.50 or 1.5e-3, into
+// a Decimal that keeps every digit after the point.
+func parseDecimal(text string) (*Decimal, bool) {
+	unscaled, scale, ok := oq_number.ParseDecimal(text)
+	if !ok {
+		return nil, false
+	}
+	return &Decimal{Unscaled: unscaled, Scale: scale}, true
+}
+
+// compareNumbers orders two numbers of any numeric type, exactly unless one
+// of them is a Float.
+func compareNumbers(a, b Object) int {
+	if a.Type() == FLOAT_OBJ || b.Type() == FLOAT_OBJ {
+		x, y := toFloat(a), toFloat(b)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	x, _ := toDecimal(a)
+	y, _ := toDecimal(b)
+	alignedA, alignedB, _ := alignScales(x, y)
+	return alignedA.Cmp(alignedB)
+}
@@ -3,14 +3,20 @@ package oq_evaluator
 import (
 	"bytes"
 	"fmt"
+	"hash/fnv"
+	"math/big"
 	"strings"
 
 	"github.com/adamerikoff/oq/internal/oq_ast"
+	"github.com/adamerikoff/oq/internal/oq_diagnostic"
+	"github.com/adamerikoff/oq/internal/oq_token"
 )
 
 const (
 	INTEGER_OBJ      = "INTEGER"
 	FLOAT_OBJ        = "FLOAT"
+	BIGINT_OBJ       = "BIGINT"
+	DECIMAL_OBJ      = "DECIMAL"
 	BOOLEAN_OBJ      = "BOOLEAN"
 	NULL_OBJ         = "NULL"
 	RETURN_VALUE_OBJ = "RETURN_VALUE"
@@ -18,6 +24,8 @@ const (
 	FUNCTION_OBJ     = "FUNCTION"
 	STRING_OBJ       = "STRING"
 	BUILTIN_OBJ      = "BUILTIN"
+	ARRAY_OBJ        = "ARRAY"
+	HASH_OBJ         = "HASH"
 )
 
 type ObjectType string
@@ -39,7 +47,42 @@ type Float struct {
 }
 
 func (i *Float) Type() ObjectType { return FLOAT_OBJ }
-func (i *Float) Inspect() string  { return fmt.Sprintf("%f", i.Value) }
+func (i *Float) Inspect() string  { return formatFloat(i.Value) }
+
+// BigInt is an integer too large for an Integer. Arithmetic promotes to it on
+// overflow and goes back to Integer as soon as the result fits again, so a
+// BigInt never holds a value an Integer could.
+type BigInt struct {
+	Value *big.Int
+}
+
+func (b *BigInt) Type() ObjectType { return BIGINT_OBJ }
+func (b *BigInt) Inspect() string  { return b.Value.String() }
+
+// Decimal is an exact decimal number, Unscaled / 10^Scale. The scale is part
+// of the value's spelling: 2.50d and 2.5d compare equal but print with the
+// digits they were written or computed with.
+type Decimal struct {
+	Unscaled *big.Int
+	Scale    int // digits after the point, never negative
+}
+
+func (d *Decimal) Type() ObjectType { return DECIMAL_OBJ }
+func (d *Decimal) Inspect() string {
+	digits := new(big.Int).Abs(d.Unscaled).String()
+	sign := ""
+	if d.Unscaled.Sign() < 0 {
+		sign = "-"
+	}
+	if d.Scale == 0 {
+		return sign + digits
+	}
+	if len(digits) <= d.Scale {
+		digits = strings.Repeat("0", d.Scale-len(digits)+1) + digits
+	}
+	point := len(digits) - d.Scale
+	return sign + digits[:point] + "." + digits[point:]
+}
 
 type Boolean struct {
 	Value bool
@@ -61,20 +104,44 @@ func (rv *ReturnValue) Type() ObjectType { return RETURN_VALUE_OBJ }
 func (rv *ReturnValue) Inspect() string  { return rv.Value.Inspect() }
 
 type Error struct {
-	Message string
+	Code     oq_diagnostic.Code
+	Args     []interface{} // values substituted into the catalog message
+	Message  string
+	Position oq_token.Position // where the error happened; zero until Eval attaches it
+	End      oq_token.Position // just past the failing node, so the whole of it is underlined
+	Dialect  string            // dialect Message is written in
+}
+
+// Localize rewrites the message in dialect.
+func (e *Error) Localize(dialect string) {
+	e.Message = oq_diagnostic.Message(dialect, e.Code, e.Args...)
+	e.Dialect = dialect
+}
+
+// Diagnostic converts the runtime error into the diagnostic form shared with
+// the lexer and parser.
+func (e *Error) Diagnostic() oq_diagnostic.Diagnostic {
+	return oq_diagnostic.New(e.Code, oq_diagnostic.Span{Start: e.Position, End: e.End}, e.Dialect, e.Args...)
 }
 
 func (e *Error) Type() ObjectType { return ERROR_OBJ }
-func (e *Error) Inspect() string  { return "ERROR: " + e.Message }
+func (e *Error) Inspect() string {
+	if e.Position.Line == 0 {
+		return "ERROR: " + e.Message
+	}
+	return fmt.Sprintf("ERROR: line %d, column %d: %s", e.Position.Line, e.Position.Column, e.Message)
+}
 
 type Environment struct {
-	store map[string]Object
-	outer *Environment
+	store          map[string]Object
+	outer          *Environment
+	scopedBuiltins bool // see ScopeBuiltins
 }
 
 func NewEnclosedEnvironment(outer *Environment) *Environment {
 	env := NewEnvironment()
 	env.outer = outer
+	env.scopedBuiltins = outer.scopedBuiltins
 	return env
 }
 
@@ -83,6 +150,13 @@ func NewEnvironment() *Environment {
 	return &Environment{store: s, outer: nil}
 }
 
+// ScopeBuiltins restricts builtins to the names of the dialect active at the
+// call site, so `len` no longer resolves inside a ~qzq section. Environments
+// enclosed afterwards inherit the setting.
+func (e *Environment) ScopeBuiltins(scoped bool) {
+	e.scopedBuiltins = scoped
+}
+
 func (e *Environment) Get(name string) (Object, bool) {
 	obj, ok := e.store[name]
 	if !ok && e.outer != nil {
@@ -95,6 +169,19 @@ func (e *Environment) Set(name string, val Object) Object {
 	return val
 }
 
+// Assign updates an existing binding in the nearest scope that declares name.
+// It reports false, and changes nothing, when no enclosing scope declares it.
+func (e *Environment) Assign(name string, val Object) (Object, bool) {
+	if _, ok := e.store[name]; ok {
+		e.store[name] = val
+		return val, true
+	}
+	if e.outer != nil {
+		return e.outer.Assign(name, val)
+	}
+	return nil, false
+}
+
 type Function struct {
 	Parameters []*oq_ast.Identifier
 	Body       *oq_ast.BlockStatement
@@ -124,11 +211,117 @@ type String struct {
 func (s *String) Type() ObjectType { return STRING_OBJ }
 func (s *String) Inspect() string  { return s.Value }
 
-type BuiltinFunction func(args ...Object) Object
-
 type Builtin struct {
-	Fn BuiltinFunction
+	Name    string // the name this object is registered under
+	Dialect string // dialect Name belongs to
+
+	definition *builtinDefinition
 }
 
 func (b *Builtin) Type() ObjectType { return BUILTIN_OBJ }
 func (b *Builtin) Inspect() string  { return "builtin function" }
+
+// Call runs the builtin for a call written in dialect.
+func (b *Builtin) Call(dialect string, args ...Object) Object {
+	return b.definition.Fn(b.Name, dialect, args...)
+}
+
+type Array struct {
+	Elements []Object
+}
+
+func (ao *Array) Type() ObjectType { return ARRAY_OBJ }
+func (ao *Array) Inspect() string {
+	var out bytes.Buffer
+	elements := []string{}
+	for _, e := range ao.Elements {
+		elements = append(elements, e.Inspect())
+	}
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+	return out.String()
+}
+
+// HashKey identifies a hashable object inside a Hash. Two objects of the same
+// type and value always produce the same HashKey.
+type HashKey struct {
+	Type  ObjectType
+	Value uint64
+}
+
+// Hashable is implemented by every object that can be used as a hash key.
+type Hashable interface {
+	Object
+	HashKey() HashKey
+}
+
+func (b *Boolean) HashKey() HashKey {
+	var value uint64
+
+	if b.Value {
+		value = 1
+	} else {
+		value = 0
+	}
+
+	return HashKey{Type: b.Type(), Value: value}
+}
+
+func (i *Integer) HashKey() HashKey {
+	return HashKey{Type: i.Type(), Value: uint64(i.Value)}
+}
+
+func (b *BigInt) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write([]byte(b.Value.String()))
+
+	return HashKey{Type: b.Type(), Value: h.Sum64()}
+}
+
+func (s *String) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write([]byte(s.Value))
+
+	return HashKey{Type: s.Type(), Value: h.Sum64()}
+}
+
+type HashPair struct {
+	Key   Object
+	Value Object
+}
+
+type Hash struct {
+	Pairs map[HashKey]HashPair
+	Keys  []HashKey // insertion order of Pairs, so Inspect and iteration are stable
+}
+
+func NewHash() *Hash {
+	return &Hash{Pairs: make(map[HashKey]HashPair)}
+}
+
+// Set stores value under key, keeping the original position of an existing key.
+func (h *Hash) Set(key Hashable, value Object) {
+	hashKey := key.HashKey()
+	if _, ok := h.Pairs[hashKey]; !ok {
+		h.Keys = append(h.Keys, hashKey)
+	}
+	h.Pairs[hashKey] = HashPair{Key: key, Value: value}
+}
+
+func (h *Hash) Type() ObjectType { return HASH_OBJ }
+func (h *Hash) Inspect() string {
+	var out bytes.Buffer
+
+	pairs := []string{}
+	for _, key := range h.Keys {
+		pair := h.Pairs[key]
+		pairs = append(pairs, fmt.Sprintf("%s: %s", pair.Key.Inspect(), pair.Value.Inspect()))
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
@@ -2,9 +2,11 @@ package oq_lexer
 
 import (
 	"fmt"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 
+	"github.com/adamerikoff/oq/internal/oq_diagnostic"
 	"github.com/adamerikoff/oq/internal/oq_token"
 )
 
@@ -14,13 +16,19 @@ type Lexer struct {
 	nextPosition    int                             // current reading position in input (after current char)
 	character       rune                            // current char under examination
 	keywords        map[string]oq_token.KeywordInfo // The currently active keyword map for this lexer instance
-
+	dialect         string                          // name of the active dialect, stamped on every token
+	line            int                             // 1-based line of the current char
+	column          int                             // 1-based column (in runes) of the current char
+	diagnostics     []oq_diagnostic.Diagnostic      // problems found while tokenizing, in input order
+	comments        []oq_token.Comment              // comments read since the last token, attached to the next one
 }
 
 func New(input string) *Lexer {
 	l := &Lexer{
 		input:    input,
 		keywords: oq_token.EngKeywords,
+		dialect:  "eng",
+		line:     1,
 	} // Default to base keywords on creation
 
 	l.readCharacter()
@@ -32,8 +40,10 @@ func New(input string) *Lexer {
 func (l *Lexer) SetDialect(dialect string) {
 	if newKeywords, ok := oq_token.AllDialectsMap[dialect]; ok {
 		l.keywords = newKeywords
+		l.dialect = dialect
 	} else {
 		l.keywords = oq_token.EngKeywords
+		l.dialect = "eng"
 		fmt.Printf("Warning: Unknown dialect '%s'. Keeping default(eng) keyword map.\n", dialect)
 	}
 }
@@ -47,11 +57,24 @@ func (l *Lexer) peekCharacter() rune {
 }
 
 func (l *Lexer) readCharacter() {
+	// Move the line/column counters past the character we are leaving behind.
+	// A column of 0 means nothing has been read yet; once we sit at EOF
+	// (currentPosition == len(input)) the counters stay where they are.
+	if l.column == 0 || l.currentPosition < len(l.input) {
+		if l.character == '\n' {
+			l.line++
+			l.column = 1
+		} else {
+			l.column++
+		}
+	}
+
 	// If we've reached the end of the input string, set the character to EOF marker (0).
 	if l.nextPosition >= len(l.input) {
 		l.character = 0 // Represents EOF
-		// currentPosition and nextPosition will implicitly remain at the end,
-		// which is correct for subsequent calls to indicate EOF.
+		// currentPosition points just past the last character, so slices ending
+		// at currentPosition include it. Subsequent calls keep returning EOF.
+		l.currentPosition = len(l.input)
 		return
 	}
 
@@ -72,22 +95,56 @@ func (l *Lexer) readCharacter() {
 	l.nextPosition += size
 }
 
+// position reports where the current character sits in the input.
+func (l *Lexer) position() oq_token.Position {
+	return oq_token.Position{Line: l.line, Column: l.column, Offset: l.currentPosition}
+}
+
+// locate records where tok, which started at start and ends at the current
+// character, sits in the source, how it was spelled and in which dialect.
+func (l *Lexer) locate(tok *oq_token.Token, start oq_token.Position) {
+	tok.Position = start
+	tok.End = l.position()
+	tok.Spelling = l.input[start.Offset:tok.End.Offset]
+	tok.Dialect = l.dialect
+	tok.Comments = l.comments
+	l.comments = nil
+}
+
 // NextToken determines the type of the next token based on the current character
 // and returns it. It also advances the lexer to the next character.
 func (l *Lexer) NextToken() oq_token.Token {
 	var tok oq_token.Token
 
-	// We can add logic here to skip whitespace if necessary
 	l.skipWhitespace()
+	for l.character == '/' && (l.peekCharacter() == '/' || l.peekCharacter() == '*') {
+		l.readComment()
+		l.skipWhitespace()
+	}
+
+	position := l.position()
 
 	switch l.character {
 	case '~':
 		// Lexer's job: recognize '~' as a distinct token.
 		// The parser will then decide what to do with a TILDE token followed by an IDENTIFIER.
 		tok = oq_token.NewToken(oq_token.TILDE, l.character)
-	case '"':
+	case '"', '`':
 		tok.Type = oq_token.STRING
-		tok.Literal = l.readString()
+		switch {
+		case l.character == '`':
+			tok.Literal = l.readRawString(position)
+		case strings.HasPrefix(l.input[l.currentPosition:], `"""`):
+			tok.Literal, tok.Parts = l.readMultiLineString(position)
+		default:
+			tok.Literal, tok.Parts = l.readString(position)
+		}
+		if tok.Parts != nil {
+			tok.Type = oq_token.INTERPOLATED
+			tok.Literal = l.input[position.Offset:l.currentPosition]
+		}
+		l.locate(&tok, position)
+		return tok // the string readers consume the closing quote themselves
 	case '=':
 		// Handle '==' (EQUAL) or '=' (ASSIGN)
 		if l.peekCharacter() == '=' {
@@ -144,8 +201,14 @@ func (l *Lexer) NextToken() oq_token.Token {
 		tok = oq_token.NewToken(oq_token.LBRACE, l.character)
 	case '}':
 		tok = oq_token.NewToken(oq_token.RBRACE, l.character)
+	case '[':
+		tok = oq_token.NewToken(oq_token.LBRACKET, l.character)
+	case ']':
+		tok = oq_token.NewToken(oq_token.RBRACKET, l.character)
 	case ',':
 		tok = oq_token.NewToken(oq_token.COMMA, l.character)
+	case ':':
+		tok = oq_token.NewToken(oq_token.COLON, l.character)
 	case '\n':
 		tok = oq_token.NewToken(oq_token.NEW_LINE, l.character)
 	case 0: // EOF
@@ -160,16 +223,12 @@ func (l *Lexer) NextToken() oq_token.Token {
 			keywordInfo := oq_token.LookupIdent(actualLiteral, l.keywords)
 
 			tok.Type = keywordInfo.Type           // The token's type comes from the lookup (e.g., FUNCTION or IDENTIFIER)
-			tok.Literal = keywordInfo.BaseLiteral // The token's literal is the *actual* text from the source
-			// tok.BaseLiteral = keywordInfo.BaseLiteral // Optionally, store the base literal here
+			tok.Literal = keywordInfo.BaseLiteral // The canonical form; the source text is kept in Spelling
+			l.locate(&tok, position)
 			return tok // `readIdentifier` already advanced `l.character`
-		} else if isDigit(l.character) {
-			tok.Literal = l.readNumber()
-			if l.containsDecimal(tok.Literal) {
-				tok.Type = oq_token.FLOAT
-			} else {
-				tok.Type = oq_token.INTEGER
-			}
+		} else if isDigit(l.character) || unicode.IsDigit(l.character) {
+			tok.Type, tok.Literal = l.readNumber(position)
+			l.locate(&tok, position)
 			return tok // `readNumber` already advanced `l.character`
 		} else {
 			tok = oq_token.NewToken(oq_token.ILLEGAL, l.character)
@@ -177,6 +236,13 @@ func (l *Lexer) NextToken() oq_token.Token {
 	}
 
 	l.readCharacter() // Advance lexer for the next token (for single-character or compound tokens)
+	l.locate(&tok, position)
+
+	if tok.Type == oq_token.ILLEGAL {
+		l.diagnostics = append(l.diagnostics, oq_diagnostic.New(oq_diagnostic.ILLEGAL_CHARACTER,
+			oq_diagnostic.TokenSpan(tok), tok.Dialect, tok.Literal))
+	}
+
 	return tok
 }
 
@@ -189,12 +255,53 @@ func (l *Lexer) skipWhitespace() {
 	}
 }
 
+// readComment reads the comment starting at the current character and keeps
+// it for the next token. A line comment stops before the newline, which is
+// still tokenized. Block comments nest, so `/* a /* b */ c */` is one comment.
+func (l *Lexer) readComment() {
+	start := l.position()
+
+	if l.peekCharacter() == '/' {
+		for l.character != '\n' && l.character != 0 {
+			l.readCharacter()
+		}
+	} else {
+		l.readCharacter() // '/'
+		l.readCharacter() // '*'
+		for depth := 1; depth > 0; {
+			switch {
+			case l.character == 0:
+				comment := oq_token.Comment{Text: l.input[start.Offset:l.currentPosition], Position: start, End: l.position()}
+				l.diagnostics = append(l.diagnostics, oq_diagnostic.New(oq_diagnostic.UNTERMINATED_COMMENT,
+					oq_diagnostic.Span{Start: start, End: comment.End}, l.dialect))
+				l.comments = append(l.comments, comment)
+				return
+			case l.character == '/' && l.peekCharacter() == '*':
+				depth++
+				l.readCharacter()
+			case l.character == '*' && l.peekCharacter() == '/':
+				depth--
+				l.readCharacter()
+			}
+			l.readCharacter()
+		}
+	}
+
+	l.comments = append(l.comments, oq_token.Comment{
+		Text:     l.input[start.Offset:l.currentPosition],
+		Position: start,
+		End:      l.position(),
+	})
+}
+
 func isLetter(character rune) bool {
 	return unicode.IsLetter(character) || character == '_'
 }
 
+// isDigit accepts only the ASCII digits 0-9. Other scripts' digits, such as
+// Arabic-Indic ٣, are reported as an error rather than read as numbers.
 func isDigit(character rune) bool {
-	return unicode.IsDigit((character))
+	return '0' <= character && character <= '9'
 }
 
 func (l *Lexer) readIdentifier() string {
@@ -205,37 +312,181 @@ func (l *Lexer) readIdentifier() string {
 	return l.input[position:l.currentPosition]
 }
 
-func (l *Lexer) readNumber() string {
-	position := l.currentPosition
-	hasDecimal := false // Track if a decimal point has been encountered
+// readNumber reads a number literal: a decimal integer, a 0x, 0b or 0o
+// integer, or a float with a fraction, an exponent or both, as in 1.5e-3. A
+// d suffix, as in 2.50d, makes a decimal number exact. Underscores may
+// separate digits. It returns the token type and the literal with the
+// underscores removed and the prefix and suffix in lower case. A
+// malformed number, such as 1.2.3 or 0xZZ, is read up to its end, reported,
+// and returned as ILLEGAL so the parser does not report it again.
+func (l *Lexer) readNumber(start oq_token.Position) (oq_token.TokenType, string) {
+	var tokenType oq_token.TokenType = oq_token.INTEGER
+	var literal strings.Builder
+	valid := true
 
-	for {
-		if unicode.IsDigit(l.character) {
-			// Continue reading digits
-		} else if l.character == '.' {
-			// If we already have a decimal, or the next char isn't a digit,
-			// then this isn't part of the number. Break.
-			if hasDecimal || !unicode.IsDigit(l.peekCharacter()) {
-				break
+	if base := l.numberBase(); base != "" {
+		literal.WriteString(base)
+		l.readCharacters(2)
+		digits, ok := l.readDigits(isDigitOfBase(base))
+		literal.WriteString(digits)
+		valid = ok && digits != ""
+	} else {
+		digits, ok := l.readDigits(isDigit)
+		literal.WriteString(digits)
+		valid = ok && digits != ""
+
+		if l.character == '.' && isDigit(l.peekCharacter()) {
+			tokenType = oq_token.FLOAT
+			l.readCharacter()
+			digits, ok := l.readDigits(isDigit)
+			literal.WriteString("." + digits)
+			valid = valid && ok
+		}
+
+		if l.character == 'e' || l.character == 'E' {
+			tokenType = oq_token.FLOAT
+			literal.WriteRune('e')
+			l.readCharacter()
+			if l.character == '+' || l.character == '-' {
+				literal.WriteRune(l.character)
+				l.readCharacter()
 			}
-			hasDecimal = true // Mark that we've seen a decimal
+			digits, ok := l.readDigits(isDigit)
+			literal.WriteString(digits)
+			valid = valid && ok && digits != ""
+		}
+
+		if l.character == 'd' || l.character == 'D' {
+			tokenType = oq_token.DECIMAL
+			literal.WriteRune('d')
+			l.readCharacter()
+		}
+	}
+
+	// Anything that could continue a number makes it malformed: 1.2.3,
+	// 12abc, 0b102. Any fraction has been read by now, so a point is too,
+	// and 1.e5 and 1..2 are reported whole rather than split at the point.
+	for isLetter(l.character) || unicode.IsDigit(l.character) || l.character == '_' || l.character == '.' {
+		valid = false
+		l.readCharacter()
+	}
+
+	if !valid {
+		spelling := l.input[start.Offset:l.currentPosition]
+		code := oq_diagnostic.MALFORMED_NUMBER
+		if strings.IndexFunc(spelling, func(r rune) bool { return unicode.IsDigit(r) && !isDigit(r) }) >= 0 {
+			code = oq_diagnostic.NON_ASCII_DIGIT
+		}
+		l.diagnostics = append(l.diagnostics, oq_diagnostic.New(code,
+			oq_diagnostic.Span{Start: start, End: l.position()}, l.dialect, spelling))
+		return oq_token.ILLEGAL, spelling
+	}
+
+	return tokenType, literal.String()
+}
+
+// numberBase returns the lower-case prefix of a 0x, 0b or 0o number starting
+// at the current character, or "" for a decimal one.
+func (l *Lexer) numberBase() string {
+	if l.character != '0' {
+		return ""
+	}
+	switch l.peekCharacter() {
+	case 'x', 'X':
+		return "0x"
+	case 'b', 'B':
+		return "0b"
+	case 'o', 'O':
+		return "0o"
+	default:
+		return ""
+	}
+}
+
+// readDigits reads digits accepted by isDigit, with single underscores
+// allowed between them, and returns the digits alone. It reports false when
+// an underscore does not sit between two digits.
+func (l *Lexer) readDigits(isDigit func(rune) bool) (string, bool) {
+	var digits strings.Builder
+	ok := true
+	previous := rune(0)
+
+	for isDigit(l.character) || l.character == '_' {
+		if l.character == '_' {
+			ok = ok && previous != 0 && previous != '_' && isDigit(l.peekCharacter())
 		} else {
-			// Not a digit and not a valid decimal point situation, so stop
-			break
+			digits.WriteRune(l.character)
 		}
-		l.readCharacter() // Advance to the next character
+		previous = l.character
+		l.readCharacter()
 	}
 
-	return l.input[position:l.currentPosition]
+	return digits.String(), ok
+}
+
+func isDigitOfBase(base string) func(rune) bool {
+	switch base {
+	case "0x":
+		return isHexDigit
+	case "0b":
+		return func(character rune) bool { return character == '0' || character == '1' }
+	default:
+		return func(character rune) bool { return '0' <= character && character <= '7' }
+	}
 }
 
-func (l *Lexer) containsDecimal(s string) bool {
-	for _, r := range s {
-		if r == '.' {
-			return true
+// Rewind moves the lexer back to tok, a token that has to be read again, for
+// example under a different dialect. The comments attached to tok are read
+// again with it. Diagnostics found at or after that point are dropped since
+// reading again reports them anew.
+func (l *Lexer) Rewind(tok oq_token.Token) {
+	position := tok.Position
+	if len(tok.Comments) > 0 {
+		position = tok.Comments[0].Position
+	}
+	l.comments = nil
+
+	l.line = position.Line
+	l.column = position.Column
+	l.currentPosition = position.Offset
+
+	if position.Offset >= len(l.input) {
+		l.character = 0
+		l.currentPosition = len(l.input)
+		l.nextPosition = len(l.input)
+	} else {
+		r, size := utf8.DecodeRuneInString(l.input[position.Offset:])
+		l.character = r
+		l.nextPosition = position.Offset + size
+	}
+
+	kept := l.diagnostics[:0]
+	for _, diagnostic := range l.diagnostics {
+		if diagnostic.Span.Start.Offset < position.Offset {
+			kept = append(kept, diagnostic)
 		}
 	}
-	return false
+	l.diagnostics = kept
+}
+
+// Sublexer returns a lexer for the expression embedded in an interpolated
+// string, reading part of this lexer's input in dialect. Positions stay those
+// of the whole input.
+func (l *Lexer) Sublexer(part oq_token.StringPart, dialect string) *Lexer {
+	sub := New(l.input[:part.End.Offset])
+	sub.SetDialect(dialect)
+	sub.Rewind(oq_token.Token{Position: part.Position})
+	return sub
+}
+
+// Diagnostics returns the problems found in the input read so far.
+func (l *Lexer) Diagnostics() []oq_diagnostic.Diagnostic {
+	return l.diagnostics
+}
+
+// Dialect returns the name of the active dialect.
+func (l *Lexer) Dialect() string {
+	return l.dialect
 }
 
 func (l *Lexer) GetActiveKeywords() map[string]oq_token.KeywordInfo {
@@ -246,16 +497,307 @@ func (l *Lexer) SetInput(input string) {
 	l.input = input
 	l.currentPosition = 0
 	l.nextPosition = 0
+	l.character = 0
+	l.line = 1
+	l.column = 0
+	l.diagnostics = nil
+	l.comments = nil
 	l.readCharacter()
 }
 
-func (l *Lexer) readString() string {
-	position := l.currentPosition + 1
+// readString reads a "..." string starting at the current character and
+// returns its value with escape sequences decoded. The string has to end on
+// the line it starts on; otherwise it is reported as unterminated and the
+// newline is left for the next token. When the string embeds `${...}`
+// expressions, it is also returned split into parts.
+func (l *Lexer) readString(start oq_token.Position) (string, []oq_token.StringPart) {
+	s := stringReader{}
+	l.readCharacter() // opening '"'
+	s.textStart = l.position()
+
 	for {
+		switch {
+		case l.character == '"':
+			s.textEnd = l.position()
+			l.readCharacter()
+			return s.finish()
+		case l.character == '\n' || l.character == 0:
+			s.textEnd = l.position()
+			l.unterminatedString(start, `"`)
+			return s.finish()
+		case l.character == '\\':
+			l.readEscape(&s.value)
+		case l.character == '$' && l.peekCharacter() == '{':
+			if !s.readInterpolation(l, false) {
+				return s.finish()
+			}
+		default:
+			s.value.WriteRune(l.character)
+			l.readCharacter()
+		}
+	}
+}
+
+// readMultiLineString reads a """...""" string, which may span several lines
+// and decodes the same escape sequences as "...". A newline right after the
+// opening quotes is not part of the value, so the text can start on its own
+// line.
+func (l *Lexer) readMultiLineString(start oq_token.Position) (string, []oq_token.StringPart) {
+	s := stringReader{}
+	l.readCharacters(3) // opening '"""'
+	if l.character == '\n' {
 		l.readCharacter()
-		if l.character == '"' || l.character == 0 {
-			break
+	}
+	s.textStart = l.position()
+
+	for {
+		switch {
+		case strings.HasPrefix(l.input[l.currentPosition:], `"""`):
+			s.textEnd = l.position()
+			l.readCharacters(3)
+			return s.finish()
+		case l.character == 0:
+			s.textEnd = l.position()
+			l.unterminatedString(start, `"""`)
+			return s.finish()
+		case l.character == '\\':
+			l.readEscape(&s.value)
+		case l.character == '$' && l.peekCharacter() == '{':
+			if !s.readInterpolation(l, true) {
+				return s.finish()
+			}
+		default:
+			s.value.WriteRune(l.character)
+			l.readCharacter()
 		}
 	}
-	return l.input[position:l.currentPosition]
+}
+
+// stringReader collects the value of a "..." or """...""" string and, once
+// the string turns out to embed expressions, its parts.
+type stringReader struct {
+	value     strings.Builder // text read since the last embedded expression
+	textStart oq_token.Position
+	textEnd   oq_token.Position
+	parts     []oq_token.StringPart // nil until the first `${`
+}
+
+// readInterpolation reads the `${...}` starting at the current character. It
+// reports false when the closing brace is missing, which also ends the string.
+func (s *stringReader) readInterpolation(l *Lexer, multiLine bool) bool {
+	opening := l.position()
+	s.textEnd = opening
+	s.flushText()
+
+	l.readCharacters(2) // '${'
+	part := oq_token.StringPart{Expression: true, Position: l.position()}
+	if !l.skipEmbedded(multiLine) {
+		l.unterminatedInterpolation(opening)
+		return false
+	}
+
+	part.End = l.position()
+	part.Text = l.input[part.Position.Offset:part.End.Offset]
+	s.parts = append(s.parts, part)
+
+	l.readCharacter() // '}'
+	s.textStart = l.position()
+	return true
+}
+
+// skipEmbedded moves to the '}' that closes the embedded expression the
+// current character is in. Strings nested in the expression, and expressions
+// nested in those, are skipped whole so their braces are not counted. It
+// reports false when the line (unless multiLine) or the input ends first.
+func (l *Lexer) skipEmbedded(multiLine bool) bool {
+	for depth := 1; ; l.readCharacter() {
+		switch l.character {
+		case 0:
+			return false
+		case '\n':
+			if !multiLine {
+				return false
+			}
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return true
+			}
+		case '"', '`':
+			if !l.skipNestedString(multiLine) {
+				return false
+			}
+		}
+	}
+}
+
+// skipNestedString moves to the closing quote of the string that starts at
+// the current character.
+func (l *Lexer) skipNestedString(multiLine bool) bool {
+	quote := l.character
+	l.readCharacter()
+
+	for l.character != quote {
+		switch {
+		case l.character == 0, l.character == '\n' && quote == '"' && !multiLine:
+			return false
+		case l.character == '\\' && quote == '"':
+			l.readCharacter()
+		case l.character == '$' && l.peekCharacter() == '{' && quote == '"':
+			l.readCharacters(2)
+			if !l.skipEmbedded(multiLine) {
+				return false
+			}
+		}
+		l.readCharacter()
+	}
+	return true
+}
+
+// flushText ends the text part read so far.
+func (s *stringReader) flushText() {
+	if s.parts == nil {
+		s.parts = []oq_token.StringPart{}
+	}
+	if s.value.Len() > 0 {
+		s.parts = append(s.parts, oq_token.StringPart{Text: s.value.String(), Position: s.textStart, End: s.textEnd})
+	}
+	s.value.Reset()
+}
+
+// finish returns the value, or for an interpolated string its parts, once
+// textEnd marks where the last text ends.
+func (s *stringReader) finish() (string, []oq_token.StringPart) {
+	if s.parts == nil {
+		return s.value.String(), nil
+	}
+	s.flushText()
+	return "", s.parts
+}
+
+// readRawString reads a `...` string. Everything up to the closing backtick,
+// backslashes and newlines included, is taken as written.
+func (l *Lexer) readRawString(start oq_token.Position) string {
+	l.readCharacter() // opening '`'
+	position := l.currentPosition
+
+	for l.character != '`' {
+		if l.character == 0 {
+			l.unterminatedString(start, "`")
+			return l.input[position:l.currentPosition]
+		}
+		l.readCharacter()
+	}
+
+	value := l.input[position:l.currentPosition]
+	l.readCharacter() // closing '`'
+	return value
+}
+
+// readEscape decodes the escape sequence starting at the backslash under the
+// current character into value. An invalid sequence is reported and kept in
+// the value as written.
+func (l *Lexer) readEscape(value *strings.Builder) {
+	start := l.position()
+	l.readCharacter() // '\'
+
+	switch l.character {
+	case 'n':
+		value.WriteRune('\n')
+	case 't':
+		value.WriteRune('\t')
+	case 'r':
+		value.WriteRune('\r')
+	case '0':
+		value.WriteRune(0)
+	case '\\', '"', '`', '$':
+		value.WriteRune(l.character)
+	case 'u':
+		l.readCharacter()
+		if character, ok := l.readUnicodeEscape(); ok {
+			value.WriteRune(character)
+			return
+		}
+		l.invalidEscape(start, value)
+		return
+	case '\n', 0:
+		// Leave the newline or EOF to end the string.
+		l.invalidEscape(start, value)
+		return
+	default:
+		l.readCharacter()
+		l.invalidEscape(start, value)
+		return
+	}
+
+	l.readCharacter()
+}
+
+// readUnicodeEscape reads the code point of a \uXXXX or \u{X...} escape; the
+// current character is the one after 'u'. It stops at the first character
+// that cannot belong to the escape.
+func (l *Lexer) readUnicodeEscape() (rune, bool) {
+	braced := l.character == '{'
+	if braced {
+		l.readCharacter()
+	}
+
+	digits := 0
+	var code rune
+	for isHexDigit(l.character) && (braced && digits < 6 || !braced && digits < 4) {
+		code = code*16 + hexValue(l.character)
+		digits++
+		l.readCharacter()
+	}
+
+	if braced {
+		if l.character != '}' || digits == 0 {
+			return 0, false
+		}
+		l.readCharacter()
+	} else if digits != 4 {
+		return 0, false
+	}
+
+	return code, utf8.ValidRune(code)
+}
+
+func (l *Lexer) invalidEscape(start oq_token.Position, value *strings.Builder) {
+	sequence := l.input[start.Offset:l.currentPosition]
+	value.WriteString(sequence)
+	l.diagnostics = append(l.diagnostics, oq_diagnostic.New(oq_diagnostic.INVALID_ESCAPE,
+		oq_diagnostic.Span{Start: start, End: l.position()}, l.dialect, sequence))
+}
+
+func (l *Lexer) unterminatedInterpolation(start oq_token.Position) {
+	l.diagnostics = append(l.diagnostics, oq_diagnostic.New(oq_diagnostic.UNTERMINATED_INTERPOLATION,
+		oq_diagnostic.Span{Start: start, End: l.position()}, l.dialect))
+}
+
+func (l *Lexer) unterminatedString(start oq_token.Position, quote string) {
+	l.diagnostics = append(l.diagnostics, oq_diagnostic.New(oq_diagnostic.UNTERMINATED_STRING,
+		oq_diagnostic.Span{Start: start, End: l.position()}, l.dialect, quote))
+}
+
+func (l *Lexer) readCharacters(n int) {
+	for i := 0; i < n; i++ {
+		l.readCharacter()
+	}
+}
+
+func isHexDigit(character rune) bool {
+	return '0' <= character && character <= '9' || 'a' <= character && character <= 'f' || 'A' <= character && character <= 'F'
+}
+
+func hexValue(character rune) rune {
+	switch {
+	case character >= 'a':
+		return character - 'a' + 10
+	case character >= 'A':
+		return character - 'A' + 10
+	default:
+		return character - '0'
+	}
 }
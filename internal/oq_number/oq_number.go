@@ -0,0 +1,54 @@
+package oq_number
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// MAX_DECIMAL_SCALE bounds how far the point of a decimal can move: the
+// digits after it, and the exponent of a literal, in either direction.
+// Beyond it, computing the power of ten alone takes too long.
+const MAX_DECIMAL_SCALE = 4096
+
+// ParseDecimal reads a plain decimal number, such as -12.50 or 1.5e-3, as an
+// unscaled integer and the count of digits after the point, keeping every
+// digit written. It is shared by the parser, which checks decimal literals,
+// and the evaluator, and reports false for malformed text or a scale beyond
+// MAX_DECIMAL_SCALE.
+func ParseDecimal(text string) (*big.Int, int, bool) {
+	mantissa, exponentText, hasExponent := strings.Cut(strings.ToLower(text), "e")
+	exponent := 0
+	if hasExponent {
+		var err error
+		if exponent, err = strconv.Atoi(exponentText); err != nil || abs(exponent) > MAX_DECIMAL_SCALE {
+			return nil, 0, false
+		}
+	}
+
+	whole, fraction, _ := strings.Cut(mantissa, ".")
+	if strings.ContainsAny(fraction, "+-") {
+		return nil, 0, false
+	}
+	unscaled, ok := new(big.Int).SetString(whole+fraction, 10)
+	if !ok {
+		return nil, 0, false
+	}
+
+	scale := len(fraction) - exponent
+	if abs(scale) > MAX_DECIMAL_SCALE {
+		return nil, 0, false
+	}
+	if scale < 0 {
+		shift := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-scale)), nil)
+		return unscaled.Mul(unscaled, shift), 0, true
+	}
+	return unscaled, scale, true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -1,35 +1,49 @@
 package oq_parser
 
 import (
-	"fmt"
+	"math/big"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/adamerikoff/oq/internal/oq_ast"
+	"github.com/adamerikoff/oq/internal/oq_diagnostic"
 	"github.com/adamerikoff/oq/internal/oq_lexer"
+	"github.com/adamerikoff/oq/internal/oq_number"
 	"github.com/adamerikoff/oq/internal/oq_token"
 )
 
 const (
 	_ int = iota
 	LOWEST
+	ASSIGNMENT  // x = y
+	LOGICAL_OR  // or
+	LOGICAL_AND // and
 	EQUALS      // ==
-	LESSGREATER // > or <
+	LESSGREATER // >, <, >= or <=
 	SUM         // +
 	PRODUCT     // *
 	PREFIX      // -X or !X
 	CALL        // myFunction(X)
+	INDEX       // array[index]
 )
 
 var precedences = map[oq_token.TokenType]int{
-	oq_token.EQUAL:     EQUALS,
-	oq_token.NOT_EQUAL: EQUALS,
-	oq_token.LESS:      LESSGREATER,
-	oq_token.GREATER:   LESSGREATER,
-	oq_token.PLUS:      SUM,
-	oq_token.MINUS:     SUM,
-	oq_token.SLASH:     PRODUCT,
-	oq_token.STAR:      PRODUCT,
-	oq_token.LPAREN:    CALL,
+	oq_token.ASSIGN:        ASSIGNMENT,
+	oq_token.OR:            LOGICAL_OR,
+	oq_token.AND:           LOGICAL_AND,
+	oq_token.EQUAL:         EQUALS,
+	oq_token.NOT_EQUAL:     EQUALS,
+	oq_token.LESS:          LESSGREATER,
+	oq_token.GREATER:       LESSGREATER,
+	oq_token.LESS_EQUAL:    LESSGREATER,
+	oq_token.GREATER_EQUAL: LESSGREATER,
+	oq_token.PLUS:          SUM,
+	oq_token.MINUS:         SUM,
+	oq_token.SLASH:         PRODUCT,
+	oq_token.STAR:          PRODUCT,
+	oq_token.LPAREN:        CALL,
+	oq_token.LBRACKET:      INDEX,
 }
 
 type (
@@ -41,7 +55,7 @@ type Parser struct {
 	l            *oq_lexer.Lexer
 	currentToken oq_token.Token
 	peekToken    oq_token.Token
-	errors       []string
+	errors       []oq_diagnostic.Diagnostic
 
 	prefixParseFunctions map[oq_token.TokenType]prefixParseFunction
 	infixParseFunctions  map[oq_token.TokenType]infixParseFunction
@@ -50,7 +64,7 @@ type Parser struct {
 func New(l *oq_lexer.Lexer) *Parser {
 	p := &Parser{
 		l:      l,
-		errors: []string{},
+		errors: []oq_diagnostic.Diagnostic{},
 	}
 
 	p.registerParsingFunctions()
@@ -67,6 +81,7 @@ func (p *Parser) registerParsingFunctions() {
 	p.registerPrefix(oq_token.IDENTIFIER, p.parseIdentifier)
 	p.registerPrefix(oq_token.INTEGER, p.parseIntegerLiteral)
 	p.registerPrefix(oq_token.FLOAT, p.parseFloatLiteral)
+	p.registerPrefix(oq_token.DECIMAL, p.parseDecimalLiteral)
 	p.registerPrefix(oq_token.EXCLAMATION, p.parsePrefixExpression)
 	p.registerPrefix(oq_token.MINUS, p.parsePrefixExpression)
 	p.registerPrefix(oq_token.TRUE, p.parseBoolean)
@@ -75,6 +90,9 @@ func (p *Parser) registerParsingFunctions() {
 	p.registerPrefix(oq_token.IF, p.parseIfExpression)
 	p.registerPrefix(oq_token.FUNCTION, p.parseFunctionLiteral)
 	p.registerPrefix(oq_token.STRING, p.parseStringLiteral)
+	p.registerPrefix(oq_token.INTERPOLATED, p.parseInterpolatedString)
+	p.registerPrefix(oq_token.LBRACKET, p.parseArrayLiteral)
+	p.registerPrefix(oq_token.LBRACE, p.parseHashLiteral)
 
 	p.infixParseFunctions = make(map[oq_token.TokenType]infixParseFunction)
 	p.registerInfix(oq_token.PLUS, p.parseInfixExpression)
@@ -85,7 +103,13 @@ func (p *Parser) registerParsingFunctions() {
 	p.registerInfix(oq_token.NOT_EQUAL, p.parseInfixExpression)
 	p.registerInfix(oq_token.LESS, p.parseInfixExpression)
 	p.registerInfix(oq_token.GREATER, p.parseInfixExpression)
+	p.registerInfix(oq_token.LESS_EQUAL, p.parseInfixExpression)
+	p.registerInfix(oq_token.GREATER_EQUAL, p.parseInfixExpression)
+	p.registerInfix(oq_token.AND, p.parseInfixExpression)
+	p.registerInfix(oq_token.OR, p.parseInfixExpression)
+	p.registerInfix(oq_token.ASSIGN, p.parseAssignExpression)
 	p.registerInfix(oq_token.LPAREN, p.parseCallExpression)
+	p.registerInfix(oq_token.LBRACKET, p.parseIndexExpression)
 
 }
 
@@ -97,8 +121,17 @@ func (p *Parser) registerInfix(tokenType oq_token.TokenType, fn infixParseFuncti
 	p.infixParseFunctions[tokenType] = fn
 }
 
-func (p *Parser) Errors() []string {
-	return p.errors
+// Errors returns every diagnostic produced while parsing, including the ones
+// reported by the lexer, ordered by their position in the source.
+func (p *Parser) Errors() []oq_diagnostic.Diagnostic {
+	errors := append([]oq_diagnostic.Diagnostic{}, p.l.Diagnostics()...)
+	errors = append(errors, p.errors...)
+
+	sort.SliceStable(errors, func(i, j int) bool {
+		return errors[i].Span.Start.Offset < errors[j].Span.Start.Offset
+	})
+
+	return errors
 }
 
 func (p *Parser) peekPrecedence() int {
@@ -117,14 +150,21 @@ func (p *Parser) curPrecedence() int {
 	return LOWEST
 }
 
+// addError records a parser diagnostic covering tok, worded in the dialect
+// that was active where tok was read.
+func (p *Parser) addError(code oq_diagnostic.Code, tok oq_token.Token, args ...interface{}) {
+	p.errors = append(p.errors, oq_diagnostic.New(code, oq_diagnostic.TokenSpan(tok), tok.Dialect, args...))
+}
+
 func (p *Parser) peekError(t oq_token.TokenType) {
-	msg := fmt.Sprintf("expected next token to be %s, got %s instead", t, p.peekToken.Type)
-	p.errors = append(p.errors, msg)
+	p.addError(oq_diagnostic.UNEXPECTED_TOKEN, p.peekToken, t, p.peekToken.Type)
 }
 
 func (p *Parser) noPrefixParseFnError(t oq_token.TokenType) {
-	msg := fmt.Sprintf("no prefix parse function for %s found", t)
-	p.errors = append(p.errors, msg)
+	if t == oq_token.ILLEGAL {
+		return // already reported by the lexer
+	}
+	p.addError(oq_diagnostic.MISSING_EXPRESSION, p.currentToken, t)
 }
 
 func (p *Parser) expectPeek(t oq_token.TokenType) bool {
@@ -171,6 +211,10 @@ func (p *Parser) parseStatement() oq_ast.Statement {
 		return p.parseLetStatement()
 	case oq_token.RETURN:
 		return p.parseReturnStatement()
+	case oq_token.WHILE:
+		return p.parseWhileStatement()
+	case oq_token.FOR:
+		return p.parseForStatement()
 	case oq_token.TILDE: // Handle TILDE directly here
 		return p.parseDialectSwitchDirective() // A new function that returns nil or a specific Directive AST node
 	case oq_token.NEW_LINE:
@@ -197,7 +241,7 @@ func (p *Parser) parseLetStatement() *oq_ast.LetStatement {
 
 	statement.Value = p.parseExpression(LOWEST)
 
-	for !p.currentTokenIs(oq_token.NEW_LINE) {
+	if p.peekTokenIs(oq_token.NEW_LINE) {
 		p.nextToken()
 	}
 
@@ -211,7 +255,7 @@ func (p *Parser) parseReturnStatement() *oq_ast.ReturnStatement {
 
 	statement.ReturnValue = p.parseExpression(LOWEST)
 
-	for !p.currentTokenIs(oq_token.NEW_LINE) {
+	if p.peekTokenIs(oq_token.NEW_LINE) {
 		p.nextToken()
 	}
 
@@ -259,27 +303,47 @@ func (p *Parser) parseIdentifier() oq_ast.Expression {
 func (p *Parser) parseIntegerLiteral() oq_ast.Expression {
 	lit := &oq_ast.IntegerLiteral{Token: p.currentToken}
 
-	value, err := strconv.ParseInt(p.currentToken.Literal, 0, 64)
+	value, ok := parseInteger(p.currentToken.Literal)
 
-	if err != nil {
-		msg := fmt.Sprintf("could not parse %q as integer", p.currentToken.Literal)
-		p.errors = append(p.errors, msg)
+	if !ok {
+		p.addError(oq_diagnostic.INVALID_INTEGER, p.currentToken, p.currentToken.Literal)
 		return nil
 	}
 
-	lit.Value = value
+	if value.IsInt64() {
+		lit.Value = value.Int64()
+	} else {
+		lit.Big = value
+	}
 
 	return lit
 }
 
+// parseInteger reads an integer literal as the lexer leaves it: decimal
+// digits, or digits after a lower-case 0x, 0b or 0o prefix. Leading zeros do
+// not make a decimal number octal. There is no upper limit.
+func parseInteger(literal string) (*big.Int, bool) {
+	base, digits := 10, literal
+	if len(literal) > 2 && literal[0] == '0' {
+		switch literal[1] {
+		case 'x':
+			base, digits = 16, literal[2:]
+		case 'b':
+			base, digits = 2, literal[2:]
+		case 'o':
+			base, digits = 8, literal[2:]
+		}
+	}
+	return new(big.Int).SetString(digits, base)
+}
+
 func (p *Parser) parseFloatLiteral() oq_ast.Expression {
 	lit := &oq_ast.FloatLiteral{Token: p.currentToken}
 
 	value, err := strconv.ParseFloat(p.currentToken.Literal, 64)
 
 	if err != nil {
-		msg := fmt.Sprintf("could not parse %q as float", p.currentToken.Literal)
-		p.errors = append(p.errors, msg)
+		p.addError(oq_diagnostic.INVALID_FLOAT, p.currentToken, p.currentToken.Literal)
 		return nil
 	}
 
@@ -288,6 +352,17 @@ func (p *Parser) parseFloatLiteral() oq_ast.Expression {
 	return lit
 }
 
+func (p *Parser) parseDecimalLiteral() oq_ast.Expression {
+	lit := &oq_ast.DecimalLiteral{Token: p.currentToken, Value: strings.TrimSuffix(p.currentToken.Literal, "d")}
+
+	if _, _, ok := oq_number.ParseDecimal(lit.Value); !ok {
+		p.addError(oq_diagnostic.INVALID_DECIMAL, p.currentToken, p.currentToken.Literal, oq_number.MAX_DECIMAL_SCALE)
+		return nil
+	}
+
+	return lit
+}
+
 func (p *Parser) parsePrefixExpression() oq_ast.Expression {
 	expression := &oq_ast.PrefixExpression{
 		Token:    p.currentToken,
@@ -315,6 +390,28 @@ func (p *Parser) parseInfixExpression(left oq_ast.Expression) oq_ast.Expression
 	return expression
 }
 
+// parseAssignExpression parses `name = value`. Assignment is right-associative,
+// so `a = b = 1` assigns 1 to b and then to a.
+func (p *Parser) parseAssignExpression(left oq_ast.Expression) oq_ast.Expression {
+	name, ok := left.(*oq_ast.Identifier)
+	if !ok {
+		target := left.String()
+		if boolean, ok := left.(*oq_ast.Boolean); ok {
+			// Quote the keyword as written, so `шын = 1` is not reported as `true`.
+			target = boolean.Token.Spelling
+		}
+		p.addError(oq_diagnostic.INVALID_ASSIGN_TARGET, p.currentToken, target)
+		return nil
+	}
+
+	expression := &oq_ast.AssignExpression{Token: p.currentToken, Name: name}
+
+	p.nextToken()
+	expression.Value = p.parseExpression(ASSIGNMENT - 1)
+
+	return expression
+}
+
 func (p *Parser) parseBoolean() oq_ast.Expression {
 	return &oq_ast.Boolean{Token: p.currentToken, Value: p.currentTokenIs(oq_token.TRUE)}
 }
@@ -332,6 +429,7 @@ func (p *Parser) parseGroupedExpression() oq_ast.Expression {
 
 func (p *Parser) parseBlockStatement() *oq_ast.BlockStatement {
 	block := &oq_ast.BlockStatement{Token: p.currentToken}
+	dialect := p.l.Dialect()
 
 	block.Statements = []oq_ast.Statement{}
 
@@ -344,18 +442,66 @@ func (p *Parser) parseBlockStatement() *oq_ast.BlockStatement {
 		}
 		p.nextToken()
 	}
+	block.Closing = p.currentToken
+
+	// A directive inside the block only lasts until its closing brace.
+	if p.l.Dialect() != dialect {
+		p.restoreDialect(dialect)
+	}
 
 	return block
 }
 
+// restoreDialect switches the lexer back to dialect. The peek token was already
+// read under the dialect being left, so it is read again.
+func (p *Parser) restoreDialect(dialect string) {
+	p.l.SetDialect(dialect)
+	p.l.Rewind(p.peekToken)
+	p.peekToken = p.l.NextToken()
+}
+
 func (p *Parser) parseIfExpression() oq_ast.Expression {
 	expression := &oq_ast.IfExpression{Token: p.currentToken}
+
+	branch := p.parseConditionalBranch()
+	if branch == nil {
+		return nil
+	}
+	expression.Branches = append(expression.Branches, branch)
+
+	for p.peekTokenIs(oq_token.ELSIF) {
+		p.nextToken()
+
+		branch := p.parseConditionalBranch()
+		if branch == nil {
+			return nil
+		}
+		expression.Branches = append(expression.Branches, branch)
+	}
+
+	if p.peekTokenIs(oq_token.ELSE) {
+		p.nextToken()
+
+		if !p.expectPeek(oq_token.LBRACE) {
+			return nil
+		}
+
+		expression.Alternative = p.parseBlockStatement()
+	}
+
+	return expression
+}
+
+// parseConditionalBranch parses `(condition) { consequence }` following an
+// `if` or `elsif` keyword, which must be the current token.
+func (p *Parser) parseConditionalBranch() *oq_ast.ConditionalBranch {
+	branch := &oq_ast.ConditionalBranch{Token: p.currentToken}
 	if !p.expectPeek(oq_token.LPAREN) {
 		return nil
 	}
 
 	p.nextToken()
-	expression.Condition = p.parseExpression(LOWEST)
+	branch.Condition = p.parseExpression(LOWEST)
 
 	if !p.expectPeek(oq_token.RPAREN) {
 		return nil
@@ -365,19 +511,63 @@ func (p *Parser) parseIfExpression() oq_ast.Expression {
 		return nil
 	}
 
-	expression.Consequence = p.parseBlockStatement()
+	branch.Consequence = p.parseBlockStatement()
 
-	if p.peekTokenIs(oq_token.ELSE) {
-		p.nextToken()
+	return branch
+}
 
-		if !p.expectPeek(oq_token.LBRACE) {
-			return nil
-		}
+func (p *Parser) parseWhileStatement() oq_ast.Statement {
+	statement := &oq_ast.WhileStatement{Token: p.currentToken}
+	if !p.expectPeek(oq_token.LPAREN) {
+		return nil
+	}
 
-		expression.Alternative = p.parseBlockStatement()
+	p.nextToken()
+	statement.Condition = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(oq_token.RPAREN) {
+		return nil
 	}
 
-	return expression
+	if !p.expectPeek(oq_token.LBRACE) {
+		return nil
+	}
+
+	statement.Body = p.parseBlockStatement()
+
+	return statement
+}
+
+func (p *Parser) parseForStatement() oq_ast.Statement {
+	statement := &oq_ast.ForStatement{Token: p.currentToken}
+	if !p.expectPeek(oq_token.LPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(oq_token.IDENTIFIER) {
+		return nil
+	}
+
+	statement.Variable = &oq_ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+
+	if !p.expectPeek(oq_token.IN) {
+		return nil
+	}
+
+	p.nextToken()
+	statement.Iterable = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(oq_token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(oq_token.LBRACE) {
+		return nil
+	}
+
+	statement.Body = p.parseBlockStatement()
+
+	return statement
 }
 
 func (p *Parser) parseFunctionParameters() []*oq_ast.Identifier {
@@ -426,32 +616,130 @@ func (p *Parser) parseFunctionLiteral() oq_ast.Expression {
 
 func (p *Parser) parseCallExpression(function oq_ast.Expression) oq_ast.Expression {
 	exp := &oq_ast.CallExpression{Token: p.currentToken, Function: function}
-	exp.Arguments = p.parseCallArguments()
+	exp.Arguments = p.parseExpressionList(oq_token.RPAREN)
+	exp.Closing = p.currentToken
 	return exp
 }
 
-func (p *Parser) parseCallArguments() []oq_ast.Expression {
-	args := []oq_ast.Expression{}
+// parseExpressionList parses comma-separated expressions up to and including
+// the end token. It is shared by call arguments and array literals.
+func (p *Parser) parseExpressionList(end oq_token.TokenType) []oq_ast.Expression {
+	list := []oq_ast.Expression{}
 
-	if p.peekTokenIs(oq_token.RPAREN) {
+	if p.peekTokenIs(end) {
 		p.nextToken()
-		return args
+		return list
 	}
 
 	p.nextToken()
-	args = append(args, p.parseExpression(LOWEST))
+	list = append(list, p.parseExpression(LOWEST))
 
 	for p.peekTokenIs(oq_token.COMMA) {
 		p.nextToken()
 		p.nextToken()
-		args = append(args, p.parseExpression(LOWEST))
+		list = append(list, p.parseExpression(LOWEST))
 	}
 
-	if !p.expectPeek(oq_token.RPAREN) {
+	if !p.expectPeek(end) {
+		return nil
+	}
+
+	return list
+}
+
+func (p *Parser) parseArrayLiteral() oq_ast.Expression {
+	array := &oq_ast.ArrayLiteral{Token: p.currentToken}
+	array.Elements = p.parseExpressionList(oq_token.RBRACKET)
+	array.Closing = p.currentToken
+	return array
+}
+
+// parseHashLiteral parses `{key: value, ...}`. Blocks are only ever parsed
+// right after the keyword that owns them (if, fn, while, ...), so a '{' reached
+// in expression position always starts a hash literal. Entries may be spread
+// over several lines.
+func (p *Parser) parseHashLiteral() oq_ast.Expression {
+	hash := &oq_ast.HashLiteral{Token: p.currentToken, Pairs: []oq_ast.HashPair{}}
+
+	p.skipPeekNewLines()
+	for !p.peekTokenIs(oq_token.RBRACE) {
+		p.nextToken()
+		key := p.parseExpression(LOWEST)
+
+		if !p.expectPeek(oq_token.COLON) {
+			return nil
+		}
+
+		p.nextToken()
+		value := p.parseExpression(LOWEST)
+
+		hash.Pairs = append(hash.Pairs, oq_ast.HashPair{Key: key, Value: value})
+
+		p.skipPeekNewLines()
+		if !p.peekTokenIs(oq_token.RBRACE) && !p.expectPeek(oq_token.COMMA) {
+			return nil
+		}
+		p.skipPeekNewLines()
+	}
+
+	if !p.expectPeek(oq_token.RBRACE) {
 		return nil
 	}
+	hash.Closing = p.currentToken
 
-	return args
+	return hash
+}
+
+// skipPeekNewLines advances past any NEW_LINE tokens waiting in peek position.
+func (p *Parser) skipPeekNewLines() {
+	for p.peekTokenIs(oq_token.NEW_LINE) {
+		p.nextToken()
+	}
+}
+
+func (p *Parser) parseIndexExpression(left oq_ast.Expression) oq_ast.Expression {
+	exp := &oq_ast.IndexExpression{Token: p.currentToken, Left: left}
+
+	if p.peekTokenIs(oq_token.COLON) {
+		p.nextToken()
+		return p.parseSliceExpression(exp.Token, left, nil)
+	}
+
+	p.nextToken()
+	exp.Index = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(oq_token.COLON) {
+		p.nextToken()
+		return p.parseSliceExpression(exp.Token, left, exp.Index)
+	}
+
+	if !p.expectPeek(oq_token.RBRACKET) {
+		return nil
+	}
+	exp.Closing = p.currentToken
+
+	return exp
+}
+
+// parseSliceExpression parses the rest of `left[start:end]` from the colon.
+func (p *Parser) parseSliceExpression(tok oq_token.Token, left, start oq_ast.Expression) oq_ast.Expression {
+	exp := &oq_ast.SliceExpression{Token: tok, Left: left, Start: start}
+
+	if p.peekTokenIs(oq_token.RBRACKET) {
+		p.nextToken()
+		exp.Closing = p.currentToken
+		return exp
+	}
+
+	p.nextToken()
+	exp.End = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(oq_token.RBRACKET) {
+		return nil
+	}
+	exp.Closing = p.currentToken
+
+	return exp
 }
 
 // parseDialectSwitchDirective would be:
@@ -464,7 +752,7 @@ func (p *Parser) parseDialectSwitchDirective() oq_ast.Statement { // Returns Sta
 		// If not an identifier, it's an error or just a standalone '~' as prefix.
 		// For now, let's treat it as an error or let it fall through to a prefix expression if needed.
 		// If it's *supposed* to be a directive, then this is an error.
-		p.errors = append(p.errors, fmt.Sprintf("expected dialect name after '~', got %s", p.peekToken.Literal))
+		p.addError(oq_diagnostic.MISSING_DIALECT_NAME, p.peekToken, p.peekToken.Spelling)
 		// Still need to consume the ~ token, perhaps.
 		p.nextToken() // Consume the non-identifier token
 		return nil    // Or return an error node
@@ -480,7 +768,7 @@ func (p *Parser) parseDialectSwitchDirective() oq_ast.Statement { // Returns Sta
 		}
 		return nil // Successfully processed directive, no AST node.
 	} else {
-		p.errors = append(p.errors, fmt.Sprintf("unknown dialect '%s'", dialectName))
+		p.addError(oq_diagnostic.UNKNOWN_DIALECT, p.currentToken, dialectName, strings.Join(availableDialects(), ", "))
 		// Consume the NEW_LINE if it exists after the dialect name
 		if p.peekTokenIs(oq_token.NEW_LINE) {
 			p.nextToken()
@@ -492,3 +780,56 @@ func (p *Parser) parseDialectSwitchDirective() oq_ast.Statement { // Returns Sta
 func (p *Parser) parseStringLiteral() oq_ast.Expression {
 	return &oq_ast.StringLiteral{Token: p.currentToken, Value: p.currentToken.Literal}
 }
+
+func (p *Parser) parseInterpolatedString() oq_ast.Expression {
+	tok := p.currentToken
+	node := &oq_ast.InterpolatedString{Token: tok}
+
+	for _, part := range tok.Parts {
+		if !part.Expression {
+			text := oq_token.Token{Type: oq_token.STRING, Literal: part.Text, Position: part.Position, End: part.End, Dialect: tok.Dialect}
+			node.Parts = append(node.Parts, &oq_ast.StringLiteral{Token: text, Value: part.Text})
+			continue
+		}
+
+		if expression := p.parseEmbeddedExpression(part, tok.Dialect); expression != nil {
+			node.Parts = append(node.Parts, expression)
+		}
+	}
+
+	return node
+}
+
+// parseEmbeddedExpression parses the source of one `${...}` with a parser of
+// its own, whose errors are reported with this parser's.
+func (p *Parser) parseEmbeddedExpression(part oq_token.StringPart, dialect string) oq_ast.Expression {
+	sub := New(p.l.Sublexer(part, dialect))
+	for sub.currentTokenIs(oq_token.NEW_LINE) {
+		sub.nextToken()
+	}
+
+	if sub.currentTokenIs(oq_token.EOF) {
+		p.addError(oq_diagnostic.EMPTY_INTERPOLATION, oq_token.Token{Position: part.Position, End: part.End, Dialect: dialect})
+		return nil
+	}
+
+	expression := sub.parseExpression(LOWEST)
+	for sub.peekTokenIs(oq_token.NEW_LINE) {
+		sub.nextToken()
+	}
+	if !sub.peekTokenIs(oq_token.EOF) {
+		sub.peekError(oq_token.EOF)
+	}
+
+	p.errors = append(p.errors, sub.Errors()...)
+	return expression
+}
+
+func availableDialects() []string {
+	names := []string{}
+	for name := range oq_token.AllDialectsMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/adamerikoff/oq/internal/oq_diagnostic"
 	"github.com/adamerikoff/oq/internal/oq_evaluator"
 	"github.com/adamerikoff/oq/internal/oq_lexer"
 	"github.com/adamerikoff/oq/internal/oq_parser"
@@ -12,10 +13,32 @@ import (
 
 const PROMPT = "🏹"
 
+// Options changes how the REPL evaluates inputs and shows results. They
+// match the command-line flags of the same names.
+type Options struct {
+	// LocalNumbers writes numbers the way the active dialect does, as in
+	// 1 234,5 for Kazakh, instead of the way they are written in code.
+	LocalNumbers bool
+	// Lang is the dialect errors are reported in; empty keeps the dialect
+	// that was active where the error happened.
+	Lang string
+	// ScopedBuiltins only accepts builtin names of the active dialect.
+	ScopedBuiltins bool
+}
+
 func Start(in io.Reader, out io.Writer) {
+	StartWithOptions(in, out, Options{})
+}
+
+func StartWithOptions(in io.Reader, out io.Writer, opts Options) {
 	scanner := bufio.NewScanner(in)
 
 	env := oq_evaluator.NewEnvironment()
+	env.ScopeBuiltins(opts.ScopedBuiltins)
+
+	// A directive stays in effect for the following inputs until another
+	// directive replaces it.
+	dialect := "eng"
 
 	for {
 		fmt.Printf(PROMPT)
@@ -27,24 +50,43 @@ func Start(in io.Reader, out io.Writer) {
 		line := scanner.Text() + "\n"
 
 		l := oq_lexer.New(line)
+		l.SetDialect(dialect)
 		p := oq_parser.New(l)
 
 		program := p.ParseProgram()
+		dialect = l.Dialect()
 		if len(p.Errors()) != 0 {
-			printParserErrors(out, p.Errors())
+			printParserErrors(out, line, p.Errors(), opts)
 			continue
 		}
 
 		evaluated := oq_evaluator.Eval(program, env)
+		if errObj, ok := evaluated.(*oq_evaluator.Error); ok {
+			io.WriteString(out, opts.localize(errObj.Diagnostic()).Render(line))
+			continue
+		}
 		if evaluated != nil {
-			io.WriteString(out, evaluated.Inspect())
+			if opts.LocalNumbers {
+				io.WriteString(out, oq_evaluator.Display(evaluated, dialect))
+			} else {
+				io.WriteString(out, evaluated.Inspect())
+			}
 			io.WriteString(out, "\n")
 		}
 	}
 }
 
-func printParserErrors(out io.Writer, errors []string) {
-	for _, msg := range errors {
-		io.WriteString(out, "\t"+msg+"\n")
+func printParserErrors(out io.Writer, line string, errors []oq_diagnostic.Diagnostic, opts Options) {
+	for _, diagnostic := range errors {
+		io.WriteString(out, opts.localize(diagnostic).Render(line))
+	}
+}
+
+// localize rewords diagnostic in opts.Lang, or leaves it as is when no
+// language is set.
+func (opts Options) localize(diagnostic oq_diagnostic.Diagnostic) oq_diagnostic.Diagnostic {
+	if opts.Lang == "" {
+		return diagnostic
 	}
+	return diagnostic.Localize(opts.Lang)
 }
@@ -8,7 +8,10 @@ const (
 	IDENTIFIER = "IDENTIFIER" // add, foobar, x, y, ...
 	INTEGER    = "INTEGER"    // 1343456
 	FLOAT      = "FLOAT"
+	DECIMAL    = "DECIMAL" // 2.50d, an exact decimal number
 	STRING     = "STRING"
+	// A string with embedded `${...}` expressions, split into Token.Parts.
+	INTERPOLATED = "INTERPOLATED"
 
 	// Operators
 	ASSIGN        = "="   // Assignment operator
@@ -28,10 +31,13 @@ const (
 
 	// Delimiters
 	COMMA    = ","        // Separator for arguments, list items
+	COLON    = ":"        // Separator between keys and values in hash literals
 	LPAREN   = "("        // Left parenthesis
 	RPAREN   = ")"        // Right parenthesis
 	LBRACE   = "{"        // Left curly brace (for blocks, objects)
 	RBRACE   = "}"        // Right curly brace
+	LBRACKET = "["        // Left square bracket (for arrays, indexing)
+	RBRACKET = "]"        // Right square bracket
 	NEW_LINE = "NEW_LINE" // Newline character
 
 	// Keywords (Reserved words with special meaning)
@@ -45,6 +51,7 @@ const (
 	RETURN   = "RETURN"   // e.g., `return` from a function
 	FOR      = "FOR"      // e.g., `for` loop
 	WHILE    = "WHILE"    // e.g., `while` loop
+	IN       = "IN"       // e.g., `in` inside a `for` loop header
 
 	// Dialect specific tokens
 	TILDE = "~" // Operator to indicate dialect switch
@@ -52,9 +59,47 @@ const (
 
 type TokenType string
 
+// Position locates a token in the source text. Line and Column are 1-based and
+// Column counts characters (runes), while Offset is the 0-based byte offset
+// into the input.
+type Position struct {
+	Line   int
+	Column int
+	Offset int
+}
+
 type Token struct {
-	Type    TokenType
-	Literal string
+	Type     TokenType
+	Literal  string       // canonical form, e.g. "let" for both `let` and `болсын`
+	Spelling string       // the token exactly as written in the source
+	Position Position     // where the token starts in the source
+	End      Position     // just past the token's last character
+	Dialect  string       // dialect that was active when the token was read
+	Comments []Comment    // comments between the previous token and this one
+	Parts    []StringPart // pieces of an INTERPOLATED string, in order
+}
+
+// StringPart is one piece of an interpolated string: decoded text, or, when
+// Expression is set, the source of an embedded `${...}` expression, which
+// Position and End locate without the surrounding `${` and `}`.
+type StringPart struct {
+	Text       string
+	Expression bool
+	Position   Position
+	End        Position
+}
+
+// Comment is a `// line` or `/* block */` comment kept as trivia on the token
+// that follows it. Text is the comment exactly as written, delimiters included.
+type Comment struct {
+	Text     string
+	Position Position // where the comment starts
+	End      Position // just past the comment's last character
+}
+
+// IsBlock reports whether c is a `/* ... */` comment.
+func (c Comment) IsBlock() bool {
+	return len(c.Text) >= 2 && c.Text[:2] == "/*"
 }
 
 type KeywordInfo struct {
@@ -84,6 +129,7 @@ var EngKeywords = map[string]KeywordInfo{
 	"or":     {Type: OR, BaseLiteral: "or"},
 	"for":    {Type: FOR, BaseLiteral: "for"},
 	"while":  {Type: WHILE, BaseLiteral: "while"},
+	"in":     {Type: IN, BaseLiteral: "in"},
 }
 
 // QZQKeywords maps Kazakh (Cyrillic) keywords to their TokenType and baseLiteral.
@@ -100,6 +146,7 @@ var QzqKeywords = map[string]KeywordInfo{
 	"немесе":       {Type: OR, BaseLiteral: "or"},
 	"үшін":         {Type: FOR, BaseLiteral: "for"},
 	"уақытша":      {Type: WHILE, BaseLiteral: "while"},
+	"ішінде":       {Type: IN, BaseLiteral: "in"},
 }
 
 // TRKKeywords maps Turkish keywords to their TokenType and baseLiteral.
@@ -116,6 +163,77 @@ var TrkKeywords = map[string]KeywordInfo{
 	"veya":     {Type: OR, BaseLiteral: "or"},
 	"için":     {Type: FOR, BaseLiteral: "for"},
 	"iken":     {Type: WHILE, BaseLiteral: "while"},
+	"içinde":   {Type: IN, BaseLiteral: "in"},
+}
+
+// RusKeywords maps Russian keywords to their TokenType and baseLiteral.
+var RusKeywords = map[string]KeywordInfo{
+	"фн":         {Type: FUNCTION, BaseLiteral: "fn"},
+	"пусть":      {Type: LET, BaseLiteral: "let"},
+	"истина":     {Type: TRUE, BaseLiteral: "true"},
+	"ложь":       {Type: FALSE, BaseLiteral: "false"},
+	"если":       {Type: IF, BaseLiteral: "if"},
+	"иначе":      {Type: ELSE, BaseLiteral: "else"},
+	"иначе_если": {Type: ELSIF, BaseLiteral: "elsif"},
+	"вернуть":    {Type: RETURN, BaseLiteral: "return"},
+	"и":          {Type: AND, BaseLiteral: "and"},
+	"или":        {Type: OR, BaseLiteral: "or"},
+	"для":        {Type: FOR, BaseLiteral: "for"},
+	"пока":       {Type: WHILE, BaseLiteral: "while"},
+	"в":          {Type: IN, BaseLiteral: "in"},
+}
+
+// UzbKeywords maps Uzbek (Latin) keywords to their TokenType and baseLiteral.
+// The letters oʻ and gʻ are written with U+02BB, which the lexer reads as a letter.
+var UzbKeywords = map[string]KeywordInfo{
+	"fn":             {Type: FUNCTION, BaseLiteral: "fn"},
+	"boʻlsin":        {Type: LET, BaseLiteral: "let"},
+	"rost":           {Type: TRUE, BaseLiteral: "true"},
+	"yolgʻon":        {Type: FALSE, BaseLiteral: "false"},
+	"agar":           {Type: IF, BaseLiteral: "if"},
+	"aks_holda":      {Type: ELSE, BaseLiteral: "else"},
+	"aks_holda_agar": {Type: ELSIF, BaseLiteral: "elsif"},
+	"qaytar":         {Type: RETURN, BaseLiteral: "return"},
+	"va":             {Type: AND, BaseLiteral: "and"},
+	"yoki":           {Type: OR, BaseLiteral: "or"},
+	"uchun":          {Type: FOR, BaseLiteral: "for"},
+	"toki":           {Type: WHILE, BaseLiteral: "while"},
+	"ichida":         {Type: IN, BaseLiteral: "in"},
+}
+
+// KgzKeywords maps Kyrgyz keywords to their TokenType and baseLiteral.
+var KgzKeywords = map[string]KeywordInfo{
+	"фн":           {Type: FUNCTION, BaseLiteral: "fn"},
+	"болсун":       {Type: LET, BaseLiteral: "let"},
+	"чын":          {Type: TRUE, BaseLiteral: "true"},
+	"жалган":       {Type: FALSE, BaseLiteral: "false"},
+	"эгер":         {Type: IF, BaseLiteral: "if"},
+	"болбосо":      {Type: ELSE, BaseLiteral: "else"},
+	"эгер_болбосо": {Type: ELSIF, BaseLiteral: "elsif"},
+	"кайтар":       {Type: RETURN, BaseLiteral: "return"},
+	"жана":         {Type: AND, BaseLiteral: "and"},
+	"же":           {Type: OR, BaseLiteral: "or"},
+	"үчүн":         {Type: FOR, BaseLiteral: "for"},
+	"азырынча":     {Type: WHILE, BaseLiteral: "while"},
+	"ичинде":       {Type: IN, BaseLiteral: "in"},
+}
+
+// QzlKeywords maps Kazakh keywords in the Latin alphabet to their TokenType
+// and baseLiteral.
+var QzlKeywords = map[string]KeywordInfo{
+	"fn":           {Type: FUNCTION, BaseLiteral: "fn"},
+	"bolsyn":       {Type: LET, BaseLiteral: "let"},
+	"şyn":          {Type: TRUE, BaseLiteral: "true"},
+	"jalğan":       {Type: FALSE, BaseLiteral: "false"},
+	"eger":         {Type: IF, BaseLiteral: "if"},
+	"äitpese":      {Type: ELSE, BaseLiteral: "else"},
+	"eger_äitpese": {Type: ELSIF, BaseLiteral: "elsif"},
+	"qaitaru":      {Type: RETURN, BaseLiteral: "return"},
+	"jäne":         {Type: AND, BaseLiteral: "and"},
+	"nemese":       {Type: OR, BaseLiteral: "or"},
+	"üşın":         {Type: FOR, BaseLiteral: "for"},
+	"uaqytşa":      {Type: WHILE, BaseLiteral: "while"},
+	"ışınde":       {Type: IN, BaseLiteral: "in"},
 }
 
 // AllDialectsMap is a convenience map to get keyword maps by dialect name.
@@ -123,6 +241,17 @@ var AllDialectsMap = map[string]map[string]KeywordInfo{
 	"eng": EngKeywords,
 	"qzq": QzqKeywords,
 	"trk": TrkKeywords,
+	"rus": RusKeywords,
+	"uzb": UzbKeywords,
+	"kgz": KgzKeywords,
+	"qzl": QzlKeywords,
+}
+
+// RegisterDialect makes keywords selectable with a `~name` directive. Callers
+// are expected to have validated the map; see oq_dialect for the file-based
+// loader.
+func RegisterDialect(name string, keywords map[string]KeywordInfo) {
+	AllDialectsMap[name] = keywords
 }
 
 // LookupIdent checks if a given identifier string is a keyword in the provided dialect map.
@@ -0,0 +1,227 @@
+package oq_translator
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/adamerikoff/oq/internal/oq_diagnostic"
+	"github.com/adamerikoff/oq/internal/oq_evaluator"
+	"github.com/adamerikoff/oq/internal/oq_lexer"
+	"github.com/adamerikoff/oq/internal/oq_token"
+)
+
+// replacement rewrites source[start:end] to text.
+type replacement struct {
+	start int
+	end   int
+	text  string
+}
+
+// Translate rewrites every keyword and builtin name in source into the target
+// dialect. Everything between tokens, and every user identifier, is copied
+// unchanged. The dialect directives of source are dropped and a single
+// `~target` directive is put at the top instead.
+func Translate(source, target string) (string, error) {
+	targetKeywords, ok := oq_token.AllDialectsMap[target]
+	if !ok {
+		return "", fmt.Errorf("unknown dialect '%s'", target)
+	}
+
+	// spellings maps canonical keywords to how target writes them.
+	spellings := map[string]string{}
+	for spelling, info := range targetKeywords {
+		spellings[info.BaseLiteral] = spelling
+	}
+
+	tokens, err := tokenize(source)
+	if err != nil {
+		return "", err
+	}
+	declared := declaredNames(tokens)
+
+	replacements := []replacement{}
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+
+		switch {
+		case tok.Type == oq_token.TILDE:
+			start, end := directiveExtent(source, tokens, i)
+			replacements = append(replacements, replacement{start: start, end: end})
+			i++ // skip the dialect name
+
+		case tok.Type == oq_token.IDENTIFIER:
+			if translated, ok := oq_evaluator.TranslateBuiltin(tok.Literal, target); ok && !declared[tok.Literal] {
+				if declared[translated] {
+					return "", fmt.Errorf("line %d, column %d: builtin %q would become %q, which the program declares itself",
+						tok.Position.Line, tok.Position.Column, tok.Literal, translated)
+				}
+				replacements = append(replacements, replacement{tok.Position.Offset, tok.End.Offset, translated})
+				continue
+			}
+			if _, ok := targetKeywords[tok.Literal]; ok {
+				return "", fmt.Errorf("line %d, column %d: identifier %q is a keyword in %s",
+					tok.Position.Line, tok.Position.Column, tok.Literal, target)
+			}
+
+		case isKeyword(tok):
+			spelling, ok := spellings[tok.Literal]
+			if !ok {
+				return "", fmt.Errorf("line %d, column %d: dialect %s has no keyword for %q",
+					tok.Position.Line, tok.Position.Column, target, tok.Literal)
+			}
+			replacements = append(replacements, replacement{tok.Position.Offset, tok.End.Offset, spelling})
+		}
+	}
+
+	var out bytes.Buffer
+	out.WriteString("~" + target + "\n")
+
+	position := 0
+	for _, r := range replacements {
+		out.WriteString(source[position:r.start])
+		out.WriteString(r.text)
+		position = r.end
+	}
+	out.WriteString(source[position:])
+
+	return out.String(), nil
+}
+
+// tokenize lexes source the way the parser would, switching keyword sets at
+// every dialect directive and back at the closing brace of the block holding
+// the directive. The tokens of expressions embedded in interpolated strings
+// follow the string's own token.
+func tokenize(source string) ([]oq_token.Token, error) {
+	return tokenizeLexer(oq_lexer.New(source))
+}
+
+func tokenizeLexer(l *oq_lexer.Lexer) ([]oq_token.Token, error) {
+	tokens := []oq_token.Token{}
+	scopes := []string{}
+
+	for tok := l.NextToken(); tok.Type != oq_token.EOF; tok = l.NextToken() {
+		// The lexer has reported what makes the token illegal: a stray
+		// character, a malformed number or an unterminated string.
+		if tok.Type == oq_token.ILLEGAL {
+			return nil, diagnosticError(l.Diagnostics()[0])
+		}
+		tokens = append(tokens, tok)
+
+		switch tok.Type {
+		case oq_token.LBRACE:
+			scopes = append(scopes, l.Dialect())
+
+		case oq_token.RBRACE:
+			if len(scopes) > 0 {
+				l.SetDialect(scopes[len(scopes)-1])
+				scopes = scopes[:len(scopes)-1]
+			}
+
+		case oq_token.INTERPOLATED:
+			for _, part := range tok.Parts {
+				if !part.Expression {
+					continue
+				}
+				embedded, err := tokenizeLexer(l.Sublexer(part, tok.Dialect))
+				if err != nil {
+					return nil, err
+				}
+				tokens = append(tokens, embedded...)
+			}
+
+		case oq_token.TILDE:
+			name := l.NextToken()
+			if name.Type != oq_token.IDENTIFIER {
+				return nil, fmt.Errorf("line %d, column %d: expected dialect name after '~', got %s",
+					name.Position.Line, name.Position.Column, name.Spelling)
+			}
+			if _, ok := oq_token.AllDialectsMap[name.Literal]; !ok {
+				return nil, fmt.Errorf("line %d, column %d: unknown dialect '%s'",
+					name.Position.Line, name.Position.Column, name.Literal)
+			}
+			l.SetDialect(name.Literal)
+			tokens = append(tokens, name)
+		}
+	}
+
+	// Comments are copied as they are, but one left open swallows the rest of
+	// the file, so it is reported rather than translated around.
+	if diagnostics := l.Diagnostics(); len(diagnostics) > 0 {
+		return nil, diagnosticError(diagnostics[0])
+	}
+
+	return tokens, nil
+}
+
+func diagnosticError(d oq_diagnostic.Diagnostic) error {
+	return fmt.Errorf("line %d, column %d: %s", d.Span.Start.Line, d.Span.Start.Column, d.Message)
+}
+
+// directiveExtent returns the source range covering the directive that starts
+// at tokens[i]. When the directive is alone on its line, the whole line,
+// indentation and newline included, is covered. Otherwise the spaces after
+// it are covered too, or the ones before it when it ends the line, so that
+// removing it leaves neither a double nor a trailing space.
+func directiveExtent(source string, tokens []oq_token.Token, i int) (int, int) {
+	start := tokens[i].Position.Offset
+	end := tokens[i+1].End.Offset
+
+	lineStart := start
+	for lineStart > 0 && isBlank(source[lineStart-1]) {
+		lineStart--
+	}
+	if lineStart == 0 || source[lineStart-1] == '\n' {
+		start = lineStart
+		if i+2 < len(tokens) && tokens[i+2].Type == oq_token.NEW_LINE {
+			end = tokens[i+2].End.Offset
+		}
+		return start, end
+	}
+
+	for end < len(source) && isBlank(source[end]) {
+		end++
+	}
+	if end == len(source) || source[end] == '\n' || source[end] == '\r' {
+		start = lineStart
+	}
+
+	return start, end
+}
+
+func isBlank(character byte) bool {
+	return character == ' ' || character == '\t'
+}
+
+// declaredNames collects the identifiers the program binds itself with let,
+// function parameters and for loops. They shadow builtins of the same name,
+// so they are never rewritten.
+func declaredNames(tokens []oq_token.Token) map[string]bool {
+	declared := map[string]bool{}
+
+	for i, tok := range tokens {
+		switch tok.Type {
+		case oq_token.LET:
+			if i+1 < len(tokens) && tokens[i+1].Type == oq_token.IDENTIFIER {
+				declared[tokens[i+1].Literal] = true
+			}
+		case oq_token.FOR:
+			if i+2 < len(tokens) && tokens[i+2].Type == oq_token.IDENTIFIER {
+				declared[tokens[i+2].Literal] = true
+			}
+		case oq_token.FUNCTION:
+			for j := i + 2; j < len(tokens) && tokens[j].Type != oq_token.RPAREN; j++ {
+				if tokens[j].Type == oq_token.IDENTIFIER {
+					declared[tokens[j].Literal] = true
+				}
+			}
+		}
+	}
+
+	return declared
+}
+
+// isKeyword reports whether tok was read from a dialect's keyword map.
+func isKeyword(tok oq_token.Token) bool {
+	info, ok := oq_token.EngKeywords[tok.Literal]
+	return ok && info.Type == tok.Type
+}
@@ -0,0 +1,237 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/adamerikoff/oq/internal/oq_diagnostic"
+	"github.com/adamerikoff/oq/internal/oq_evaluator"
+	"github.com/adamerikoff/oq/internal/oq_lexer"
+	"github.com/adamerikoff/oq/internal/oq_parser"
+)
+
+func TestParserDiagnostics(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedCode    oq_diagnostic.Code
+		expectedLine    int
+		expectedColumn  int
+		expectedEndCol  int
+		expectedMessage string
+	}{
+		{"let x = 5\nlet = 10\n", oq_diagnostic.UNEXPECTED_TOKEN, 2, 5, 6,
+			"expected next token to be IDENTIFIER, got = instead"},
+		{"let total = 1 @ 2\n", oq_diagnostic.ILLEGAL_CHARACTER, 1, 15, 16,
+			"illegal character \"@\""},
+		{"~klingon\n", oq_diagnostic.UNKNOWN_DIALECT, 1, 2, 9,
+			"unknown dialect 'klingon'"},
+		{"1 = 2\n", oq_diagnostic.INVALID_ASSIGN_TARGET, 1, 3, 4,
+			"cannot assign to 1"},
+	}
+
+	for _, tt := range tests {
+		l := oq_lexer.New(tt.input)
+		p := oq_parser.New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("expected diagnostics for %q", tt.input)
+			continue
+		}
+
+		d := errors[0]
+		if d.Code != tt.expectedCode {
+			t.Errorf("wrong code. expected=%s, got=%s", tt.expectedCode, d.Code)
+		}
+		if d.Severity != oq_diagnostic.ERROR {
+			t.Errorf("wrong severity. got=%s", d.Severity)
+		}
+		if d.Span.Start.Line != tt.expectedLine || d.Span.Start.Column != tt.expectedColumn {
+			t.Errorf("wrong span start. expected=%d:%d, got=%d:%d",
+				tt.expectedLine, tt.expectedColumn, d.Span.Start.Line, d.Span.Start.Column)
+		}
+		if d.Span.End.Column != tt.expectedEndCol {
+			t.Errorf("wrong span end column. expected=%d, got=%d", tt.expectedEndCol, d.Span.End.Column)
+		}
+		if d.Message != tt.expectedMessage {
+			t.Errorf("wrong message. expected=%q, got=%q", tt.expectedMessage, d.Message)
+		}
+	}
+}
+
+func TestIllegalCharacterReportedOnce(t *testing.T) {
+	l := oq_lexer.New("let x = @\n")
+	p := oq_parser.New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) != 1 {
+		t.Fatalf("expected exactly 1 diagnostic. got=%d (%v)", len(errors), errors)
+	}
+	if errors[0].Code != oq_diagnostic.ILLEGAL_CHARACTER {
+		t.Errorf("wrong code. got=%s", errors[0].Code)
+	}
+}
+
+func TestDiagnosticRender(t *testing.T) {
+	source := "let x = 5\nlet ~ = 10\n"
+
+	l := oq_lexer.New(source)
+	p := oq_parser.New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) == 0 {
+		t.Fatalf("expected diagnostics")
+	}
+
+	expected := "error[P001]: expected next token to be IDENTIFIER, got ~ instead\n" +
+		"  --> line 2, column 5\n" +
+		"   |\n" +
+		" 2 | let ~ = 10\n" +
+		"   |     ^\n"
+
+	if errors[0].Render(source) != expected {
+		t.Errorf("wrong render.\nexpected=\n%s\ngot=\n%s", expected, errors[0].Render(source))
+	}
+}
+
+func TestRuntimeDiagnostic(t *testing.T) {
+	source := "let name = \"oQ\"\n\tgreeting + name\n"
+
+	evaluated := testEval(source)
+	errObj, ok := evaluated.(*oq_evaluator.Error)
+	if !ok {
+		t.Fatalf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+	}
+	if errObj.Code != oq_diagnostic.IDENTIFIER_NOT_FOUND {
+		t.Errorf("wrong code. got=%s", errObj.Code)
+	}
+
+	expected := "error[R003]: identifier not found: greeting\n" +
+		"  --> line 2, column 2\n" +
+		"   |\n" +
+		" 2 | \tgreeting + name\n" +
+		"   | \t^^^^^^^^\n" +
+		"  = hint: declare it with `let` before using it\n"
+
+	rendered := errObj.Diagnostic().Render(source)
+	if rendered != expected {
+		t.Errorf("wrong render.\nexpected=\n%s\ngot=\n%s", expected, rendered)
+	}
+}
+
+func TestRuntimeDiagnosticUnderlinesExpression(t *testing.T) {
+	source := "let size = len(5)\n"
+
+	errObj, ok := testEval(source).(*oq_evaluator.Error)
+	if !ok {
+		t.Fatalf("no error object returned")
+	}
+
+	expected := "error[R010]: argument to `len` not supported, got INTEGER\n" +
+		"  --> line 1, column 12\n" +
+		"   |\n" +
+		" 1 | let size = len(5)\n" +
+		"   |            ^^^^^^\n"
+
+	rendered := errObj.Diagnostic().Render(source)
+	if rendered != expected {
+		t.Errorf("wrong render.\nexpected=\n%s\ngot=\n%s", expected, rendered)
+	}
+}
+
+func TestLocalizedDiagnostics(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedDialect string
+		expectedMessage string
+	}{
+		{"~qzq\nболсын = 10\n", "qzq",
+			"келесі лексема IDENTIFIER болуы керек еді, оның орнына = алынды"},
+		{"~trk\n1 = 2\n", "trk", "1 öğesine atama yapılamaz"},
+		{"~qzq\nболсын х = 1\n~eng\nlet = 2\n", "eng",
+			"expected next token to be IDENTIFIER, got = instead"},
+	}
+
+	for _, tt := range tests {
+		l := oq_lexer.New(tt.input)
+		p := oq_parser.New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("expected diagnostics for %q", tt.input)
+			continue
+		}
+		if errors[0].Dialect != tt.expectedDialect {
+			t.Errorf("wrong dialect. expected=%q, got=%q", tt.expectedDialect, errors[0].Dialect)
+		}
+		if errors[0].Message != tt.expectedMessage {
+			t.Errorf("wrong message. expected=%q, got=%q", tt.expectedMessage, errors[0].Message)
+		}
+	}
+}
+
+func TestLocalizedRuntimeErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"~qzq\nбелгісіз", "идентификатор табылмады: белгісіз"},
+		{"~trk\n5 + doğru", "tür uyuşmazlığı: INTEGER + BOOLEAN"},
+		{"~trk\nolsun f = fn() { -doğru }\n~eng\nf()", "bilinmeyen operatör: -BOOLEAN"},
+		{"~qzq\nқосу(1, 2)", "`қосу` аргументі ARRAY болуы керек, INTEGER алынды"},
+		{"қосу(1, 2)", "argument to `қосу` must be ARRAY, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		errObj, ok := testEval(tt.input).(*oq_evaluator.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q", tt.input)
+			continue
+		}
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong message. expected=%q, got=%q", tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestDiagnosticLocalize(t *testing.T) {
+	input := "~trk\nolsun x = y\n"
+	errObj, ok := testEval(input).(*oq_evaluator.Error)
+	if !ok {
+		t.Fatalf("no error object returned")
+	}
+
+	expected := `error[R003]: identifier not found: y
+  --> line 2, column 11
+   |
+ 2 | olsun x = y
+   |           ^
+  = hint: declare it with ` + "`let`" + ` before using it
+`
+	if got := errObj.Diagnostic().Localize("eng").Render(input); got != expected {
+		t.Errorf("wrong render.\nexpected=\n%s\ngot=\n%s", expected, got)
+	}
+
+	if got := errObj.Diagnostic().String(); got != "satır 2, sütun 11: tanımlayıcı bulunamadı: y" {
+		t.Errorf("wrong string. got=%q", got)
+	}
+}
+
+func TestMessageCatalogComplete(t *testing.T) {
+	// Dialects loaded from files may leave codes untranslated; the built-in
+	// ones may not.
+	for _, dialect := range []string{"eng", "qzq", "trk", "rus", "uzb", "kgz", "qzl"} {
+		messages := oq_diagnostic.Messages[dialect]
+		for code := range oq_diagnostic.Messages[oq_diagnostic.DEFAULT_DIALECT] {
+			if _, ok := messages[code]; !ok {
+				t.Errorf("dialect %s has no message for %s", dialect, code)
+			}
+		}
+		if _, ok := oq_diagnostic.LabelsByDialect[dialect]; !ok {
+			t.Errorf("dialect %s has no labels", dialect)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/adamerikoff/oq/internal/oq_dialect"
+	"github.com/adamerikoff/oq/internal/oq_evaluator"
+	"github.com/adamerikoff/oq/internal/oq_token"
+)
+
+var (
+	loadTestDialects sync.Once
+	testDialects     []string
+	testDialectsErr  error
+)
+
+// Dialects are registered globally, so the test files are loaded only once
+// per test binary. The same directory is listed under several spellings,
+// which Load must read only once.
+func loadDialects() ([]string, error) {
+	loadTestDialects.Do(func() {
+		dirs := []string{"testdata/missing", "testdata/dialects", "./testdata/dialects/"}
+		if abs, err := filepath.Abs("testdata/dialects"); err == nil {
+			dirs = append(dirs, abs)
+		}
+		testDialects, testDialectsErr = oq_dialect.Load(dirs)
+	})
+	return testDialects, testDialectsErr
+}
+
+func TestLoadDialectFiles(t *testing.T) {
+	names, err := loadDialects()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(names) != 1 || names[0] != "deu" {
+		t.Fatalf("wrong dialects loaded. got=%v", names)
+	}
+	if _, ok := oq_token.AllDialectsMap["deu"]; !ok {
+		t.Fatalf("deu is not registered")
+	}
+
+	input := `~deu
+sei summe = 0
+für (x in [1, 2, 3]) {
+summe = summe + x
+}
+wenn (länge(anhängen([1], 2)) == 2 und wahr) { summe } sonst { 0 }
+`
+	testIntegerObject(t, testEval(input), 6)
+
+	errObj, ok := testEval("~deu\nsei x = 1 + falsch").(*oq_evaluator.Error)
+	if !ok {
+		t.Fatalf("no error object returned")
+	}
+	if errObj.Message != "Typkonflikt: INTEGER + BOOLEAN" {
+		t.Errorf("wrong message. got=%q", errObj.Message)
+	}
+	if got := errObj.Diagnostic().String(); got != "Zeile 2, Spalte 11: Typkonflikt: INTEGER + BOOLEAN" {
+		t.Errorf("wrong string. got=%q", got)
+	}
+
+	// Codes without a translation fall back to English.
+	errObj, ok = testEval("~deu\nerstes(1)").(*oq_evaluator.Error)
+	if !ok {
+		t.Fatalf("no error object returned")
+	}
+	if errObj.Message != "argument to `erstes` must be ARRAY, got INTEGER" {
+		t.Errorf("wrong message. got=%q", errObj.Message)
+	}
+}
+
+func TestInvalidDialectDefinitions(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{`{"name": "trk"}`, `dialect "trk" is already defined`},
+		{`{"name": "x1"}`, `dialect name "x1" is not a valid identifier`},
+		{`{"name": "aaa", "keywords": {"loop": "x"}}`, `unknown keyword "loop"`},
+		{`{"name": "aaa", "keywords": {"let": "x", "fn": "x"}}`, `keyword "x" is used for both "fn" and "let"`},
+		{`{"name": "aaa", "keywords": {"let": "x"}, "booleans": {"true": "x"}}`, `keyword "x" is used for both "let" and "true"`},
+		{`{"name": "aaa", "builtins": {"len": "first"}}`, `alias "first" for "len" is already the name of builtin "first"`},
+		{`{"name": "aaa", "builtins": {"len": "y", "rest": "y"}}`, `builtin alias "y" is used for both "len" and "rest"`},
+		{`{"name": "aaa", "keywords": {"let": "y"}, "builtins": {"len": "y"}}`, `builtin alias "y" for "len" is already the keyword "let"`},
+		{`{"name": "aaa", "builtins": {"size": "y"}}`, `unknown builtin "size"`},
+		{`{"name": "aaa", "messages": {"X999": "?"}}`, `unknown diagnostic code "X999"`},
+		{`{"name": "aaa", "keyword": {}}`, `unknown field "keyword"`},
+	}
+
+	for _, tt := range tests {
+		definition, err := oq_dialect.Parse([]byte(tt.input))
+		if err == nil {
+			err = oq_dialect.Register(definition)
+		}
+		if err == nil {
+			t.Errorf("expected error for %s", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("wrong error. expected to contain %q, got=%q", tt.expectedError, err.Error())
+		}
+	}
+
+	if _, ok := oq_token.AllDialectsMap["aaa"]; ok {
+		t.Errorf("invalid definition was registered")
+	}
+}
+
+func TestFirstDialectDirectoryWins(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	files := map[string]string{
+		first:  `{"name": "zzz", "keywords": {"let": "setze"}}`,
+		second: `{"name": "zzz", "keywords": {"let": "lass"}}`,
+	}
+	for dir, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, "zzz.json"), []byte(content), 0o644); err != nil {
+			t.Fatalf("could not write dialect file: %v", err)
+		}
+	}
+
+	names, err := oq_dialect.Load([]string{first, second})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(names) != 1 || names[0] != "zzz" {
+		t.Fatalf("wrong dialects loaded. got=%v", names)
+	}
+	testIntegerObject(t, testEval("~zzz\nsetze x = 2\nx"), 2)
+}
+
+func TestBuiltinAliasAlreadyTaken(t *testing.T) {
+	err := oq_evaluator.RegisterBuiltinAlias("trk", "len", "boyu")
+	if err == nil || !strings.Contains(err.Error(), `builtin "len" already has the alias "uzunluk" in dialect "trk"`) {
+		t.Fatalf("expected the alias to be rejected. got=%v", err)
+	}
+
+	if name, _ := oq_evaluator.TranslateBuiltin("len", "trk"); name != "uzunluk" {
+		t.Errorf("alias was replaced. got=%q", name)
+	}
+	if _, ok := testEval("~trk\nboyu").(*oq_evaluator.Error); !ok {
+		t.Errorf("rejected alias was registered")
+	}
+}
@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/adamerikoff/oq/internal/oq_evaluator"
@@ -31,6 +32,36 @@ func TestFunctionApplication(t *testing.T) {
 		testIntegerObject(t, testEval(tt.input), tt.expected)
 	}
 }
+func TestLogicalOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"true and true ", true},
+		{"true and false ", false},
+		{"false and true ", false},
+		{"false or true ", true},
+		{"false or false ", false},
+		{"1 < 2 and 2 < 3 ", true},
+		{"1 > 2 or 2 > 3 ", false},
+		{"1 and \"x\" ", true},
+		{"if (false) { 1 } or false ", false},
+		{"false and undefined ", false},
+		{"true or undefined ", true},
+		{`let boom = fn() { 1 + true }
+		false and boom() `, false},
+		{"~qzq\nшын және жалған ", false},
+		{"~qzq\nжалған немесе шын ", true},
+		{"~trk\ndoğru ve doğru ", true},
+		{"~trk\nyanlış veya yanlış ", false},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testBooleanObject(t, evaluated, tt.expected)
+	}
+}
+
 func TestFunctionObject(t *testing.T) {
 	input := "fn(x) { x + 2 } "
 
@@ -78,6 +109,48 @@ func TestLetStatementsEval(t *testing.T) {
 	}
 }
 
+func TestAssignExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{`let a = 5
+		a = 10
+		a `, 10},
+		{`let a = 1
+		let b = 2
+		a = b = 7
+		a + b `, 14},
+		{`let a = 5
+		a = a * 2 `, 10},
+		{`let counter = 0
+		let increment = fn() { counter = counter + 1 }
+		increment()
+		increment()
+		counter `, 2},
+		{`let sum = 0
+		for (i in 5) { sum = sum + i }
+		sum `, 10},
+		{`let i = 0
+		while (i < 4) { i = i + 1 }
+		i `, 4},
+		{`let x = 1
+		let shadow = fn() {
+			let x = 2
+			x = 3
+		}
+		shadow()
+		x `, 1},
+		{`~qzq
+		болсын x = 1
+		егер (шын) { x = 2 }
+		x `, 2},
+	}
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
 func TestIfElseExpressions(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -90,6 +163,11 @@ func TestIfElseExpressions(t *testing.T) {
 		{"if (1 > 2) { 10 }", nil},
 		{"if (1 > 2) { 10 } else { 20 }", 20},
 		{"if (1 < 2) { 10 } else { 20 }", 10},
+		{"if (1 > 2) { 10 } elsif (2 > 1) { 20 } else { 30 }", 20},
+		{"if (1 > 2) { 10 } elsif (2 > 3) { 20 } else { 30 }", 30},
+		{"if (1 > 2) { 10 } elsif (2 > 3) { 20 }", nil},
+		{"if (1 < 2) { 10 } elsif (2 > 1) { 20 }", 10},
+		{"if (false) { 10 } elsif (true) { 20 } elsif (true) { 30 }", 20},
 	}
 
 	for _, tt := range tests {
@@ -258,6 +336,19 @@ func TestEvalBooleanExpression(t *testing.T) {
 		{"1 != 1 ", false},
 		{"1 == 2 ", false},
 		{"1 != 2 ", true},
+		{"1 <= 2 ", true},
+		{"2 <= 2 ", true},
+		{"3 <= 2 ", false},
+		{"1 >= 2 ", false},
+		{"2 >= 2 ", true},
+		{"3 >= 2 ", true},
+		{"1.5 <= 1.5 ", true},
+		{"1.5 >= 2.5 ", false},
+		{"2.5 >= 1.5 ", true},
+		{"1 <= 1.5 ", true},
+		{"2 >= 2.0 ", true},
+		{"2.5 <= 2 ", false},
+		{"(1 <= 2) == true ", true},
 		{"true == true ", true},
 		{"false == false ", true},
 		{"true == false ", false},
@@ -384,6 +475,36 @@ func TestErrorHandling(t *testing.T) {
 			`"Hello" - "World" `,
 			"unknown operator: STRING - STRING",
 		},
+		{
+			"5[0] ",
+			"index operator not supported: INTEGER",
+		},
+		{
+			`{"name": "oQ"}[fn(x) { x }] `,
+			"unusable as hash key: FUNCTION",
+		},
+		{
+			`{[1]: 2} `,
+			"unusable as hash key: ARRAY",
+		},
+		{
+			"undeclared = 5 ",
+			"assignment to undeclared identifier: undeclared",
+		},
+		{
+			`let f = fn() { let local = 1 }
+			f()
+			local = 2 `,
+			"assignment to undeclared identifier: local",
+		},
+		{
+			"true and undefined ",
+			"identifier not found: undefined",
+		},
+		{
+			"false or 1 + true ",
+			"type mismatch: INTEGER + BOOLEAN",
+		},
 	}
 	for _, tt := range tests {
 		evaluated := testEval(tt.input)
@@ -415,6 +536,398 @@ func TestStringLiteral(t *testing.T) {
 	}
 }
 
+func TestStringInterpolation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let name = "Alia"
+"Hello, ${name}!"`, "Hello, Alia!"},
+		{`let n = 3
+"${n} + 1 = ${n + 1}, ${n > 2}"`, "3 + 1 = 4, true"},
+		{`"${len([1, 2])}${"nested ${"}"}"}"`, "2nested }"},
+		{`"\${kept}"`, "${kept}"},
+		{"~qzq\nболсын аты = \"Әлия\"\n\"Сәлем, ${аты}! ${ұзындығы([1])}\"", "Сәлем, Әлия! 1"},
+		{"let x = 1\n\"\"\"\nx = ${\n  x\n}\n\"\"\"", "x = 1\n"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		str, ok := evaluated.(*oq_evaluator.String)
+		if !ok {
+			t.Errorf("%q - object is not String. got=%T (%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if str.Value != tt.expected {
+			t.Errorf("%q - wrong value. expected=%q, got=%q", tt.input, tt.expected, str.Value)
+		}
+	}
+
+	evaluated := testEval(`"a ${missing} b"`)
+	errObj, ok := evaluated.(*oq_evaluator.Error)
+	if !ok {
+		t.Fatalf("expected an error, got=%T (%+v)", evaluated, evaluated)
+	}
+	if errObj.Position.Column != 6 {
+		t.Errorf("error points at the wrong column. expected=6, got=%d", errObj.Position.Column)
+	}
+}
+
+func TestUnicodeStrings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`ұзындығы("Әлия")`, 4},
+		{`uzunluk("Şükrü Güneş")`, 11},
+		{`len("😀ё")`, 2},
+		{`"Әлия"[0]`, "Ә"},
+		{`"Әлия"[3]`, "я"},
+		{`"Әлия"[4]`, nil},
+		{`"Әлия"[-1]`, nil},
+		{`"Қазақстан"[0:5]`, "Қазақ"},
+		{`"Қазақстан"[5:]`, "стан"},
+		{`"Türkçe"[:3]`, "Tür"},
+		{`"ığüşöç"[2:100]`, "üşöç"},
+		{`"ığüşöç"[4:2]`, ""},
+		{`[1, 2, 3, 4][1:3]`, []int{2, 3}},
+		{`[1, 2, 3][:]`, []int{1, 2, 3}},
+		{`"abc"["a":]`, "slice bounds must be INTEGER, got STRING"},
+		{`5[1:2]`, "index operator not supported: INTEGER"},
+		{`let out = ""
+for (c in "Әлия") { out = c + out }
+out`, "яилӘ"},
+		{`~trk
+olsun sayı = 0
+için (harf içinde "çiğdem") { sayı = sayı + 1 }
+sayı`, 6},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case nil:
+			testNullObject(t, evaluated)
+		case []int:
+			array, ok := evaluated.(*oq_evaluator.Array)
+			if !ok {
+				t.Errorf("%q - object is not Array. got=%T (%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+			if len(array.Elements) != len(expected) {
+				t.Errorf("%q - wrong number of elements. expected=%d, got=%d", tt.input, len(expected), len(array.Elements))
+				continue
+			}
+			for i, element := range expected {
+				testIntegerObject(t, array.Elements[i], int64(element))
+			}
+		case string:
+			switch object := evaluated.(type) {
+			case *oq_evaluator.String:
+				if object.Value != expected {
+					t.Errorf("%q - wrong string. expected=%q, got=%q", tt.input, expected, object.Value)
+				}
+			case *oq_evaluator.Error:
+				if object.Message != expected {
+					t.Errorf("%q - wrong error message. expected=%q, got=%q", tt.input, expected, object.Message)
+				}
+			default:
+				t.Errorf("%q - object is not String or Error. got=%T (%+v)", tt.input, evaluated, evaluated)
+			}
+		}
+	}
+}
+
+func TestLocaleCaseMapping(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"~trk\nbüyük_harf(\"istanbul ılık\")", "İSTANBUL ILIK"},
+		{"upper(\"istanbul\")", "ISTANBUL"},
+		{"~trk\nküçük_harf(\"İSTANBUL IRMAK\")", "istanbul ırmak"},
+		{"lower(\"IRMAK\")", "irmak"},
+		{"~trk\nbaşlık(\"izmir ve istanbul\")", "İzmir Ve İstanbul"},
+		{"title(\"DON'T stop\")", "Don't Stop"},
+		{"~qzq\nбас_әріп(\"әлия\")", "ӘЛИЯ"},
+		{"~qzq\nбас_әріптен(\"қазақ ТІЛІ\")", "Қазақ Тілі"},
+		{"~qzl\nbas_ärıp(\"kıtap iş\")", "KITAP İŞ"},
+		{"upper(1)", "argument to `upper` must be STRING, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch object := evaluated.(type) {
+		case *oq_evaluator.String:
+			if object.Value != tt.expected {
+				t.Errorf("%q - wrong string. expected=%q, got=%q", tt.input, tt.expected, object.Value)
+			}
+		case *oq_evaluator.Error:
+			if object.Message != tt.expected {
+				t.Errorf("%q - wrong error message. expected=%q, got=%q", tt.input, tt.expected, object.Message)
+			}
+		default:
+			t.Errorf("%q - object is not String or Error. got=%T (%+v)", tt.input, evaluated, evaluated)
+		}
+	}
+}
+
+func TestCollation(t *testing.T) {
+	sorted := []struct {
+		input    string
+		expected []string
+	}{
+		{"~qzq\nсұрыптау([\"өрік\", \"оқу\", \"аяқ\", \"әке\", \"бала\", \"ағаш\"])",
+			[]string{"ағаш", "аяқ", "әке", "бала", "оқу", "өрік"}},
+		{"~trk\nsırala([\"zeytin\", \"çay\", \"ilik\", \"ılık\", \"şeker\", \"cam\", \"Ülke\", \"uzun\"])",
+			[]string{"cam", "çay", "ılık", "ilik", "şeker", "uzun", "Ülke", "zeytin"}},
+		{"sort([\"b\", \"A\", \"a\", \"B\"])", []string{"a", "A", "b", "B"}},
+		{"sort([3, 1.5, 2])", []string{"1.5", "2", "3"}},
+	}
+
+	for _, tt := range sorted {
+		evaluated := testEval(tt.input)
+		array, ok := evaluated.(*oq_evaluator.Array)
+		if !ok {
+			t.Errorf("%q - object is not Array. got=%T (%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		got := []string{}
+		for _, element := range array.Elements {
+			got = append(got, element.Inspect())
+		}
+		if strings.Join(got, " ") != strings.Join(tt.expected, " ") {
+			t.Errorf("%q - wrong order. expected=%v, got=%v", tt.input, tt.expected, got)
+		}
+	}
+
+	compared := []struct {
+		input    string
+		expected bool
+	}{
+		{"~qzq\n\"әке\" < \"бала\"", true},
+		{"\"әке\" < \"бала\"", false},
+		{"~trk\n\"ılık\" < \"ilik\"", true},
+		{"\"a\" < \"B\"", true},
+		{"\"a\" <= \"a\"", true},
+		{"\"Әлия\" == \"Әлия\"", true},
+		{"\"Әлия\" != \"әлия\"", true},
+		{"~qzq\n\"ұл\" > \"үй\"", false},
+	}
+
+	for _, tt := range compared {
+		testBooleanObject(t, testEval(tt.input), tt.expected)
+	}
+
+	evaluated := testEval(`sort([1, "a"])`)
+	errObj, ok := evaluated.(*oq_evaluator.Error)
+	if !ok {
+		t.Fatalf("expected an error, got=%T (%+v)", evaluated, evaluated)
+	}
+	if errObj.Message != "`sort` sorts only strings or only numbers, got STRING" {
+		t.Errorf("wrong error message. got=%q", errObj.Message)
+	}
+}
+
+func TestNumberFormatting(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"25.0", "25.0"},
+		{"0.1 + 0.2", "0.30000000000000004"},
+		{"1.5 * 3", "4.5"},
+		{"format(1234567.25)", "1,234,567.25"},
+		{"format(-1234, 2)", "-1,234.00"},
+		{"format(2.345, 1)", "2.3"},
+		{"~qzq\nпішімдеу(1234567.25)", "1\u00a0234\u00a0567,25"},
+		{"~trk\nbiçimle(1234567.25)", "1.234.567,25"},
+		{"~rus\nформатировать(0.5, 3)", "0,500"},
+		{"format(\"1\")", "argument to `format` must be a number, got STRING"},
+		{"format(1, 2, 3)", "wrong number of arguments. got=3, want=1-2"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		var got string
+		switch object := evaluated.(type) {
+		case *oq_evaluator.String:
+			got = object.Value
+		case *oq_evaluator.Error:
+			got = object.Message
+		default:
+			got = evaluated.Inspect()
+		}
+		if got != tt.expected {
+			t.Errorf("%q - expected=%q, got=%q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestNumberParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`parse_number("1,234")`, 1234},
+		{`parse_number(" -42 ")`, -42},
+		{`parse_number("1,234.5")`, 1234.5},
+		{"~qzq\nсанға_айналдыру(\"1 234,5\")", 1234.5},
+		{"~qzq\nсанға_айналдыру(\"1\u00a0234\")", 1234},
+		{"~trk\nsayıya_çevir(\"1.234\")", 1234},
+		{"~trk\nsayıya_çevir(\"3,75\")", 3.75},
+		{"~trk\nsayıya_çevir(biçimle(-9876543.21))", -9876543.21},
+		{`parse_number("12abc")`, "`parse_number` cannot read \"12abc\" as a number"},
+		{`parse_number("NaN")`, "`parse_number` cannot read \"NaN\" as a number"},
+		{`parse_number("-12,345,678")`, -12345678},
+		{`parse_number("1,5")`, "`parse_number` cannot read \"1,5\" as a number"},
+		{`parse_number("12,34")`, "`parse_number` cannot read \"12,34\" as a number"},
+		{`parse_number("1234,567")`, "`parse_number` cannot read \"1234,567\" as a number"},
+		{`parse_number("1.234,5")`, "`parse_number` cannot read \"1.234,5\" as a number"},
+		{"~trk\nsayıya_çevir(\"1.5\")", "`sayıya_çevir`, \"1.5\" değerini sayı olarak okuyamıyor"},
+		{"~qzq\nсанға_айналдыру(\"1 2\")", "`санға_айналдыру` \"1 2\" мәтінін сан ретінде оқи алмайды"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case float64:
+			number, ok := evaluated.(*oq_evaluator.Float)
+			if !ok || number.Value != expected {
+				t.Errorf("%q - expected Float %v, got=%T (%+v)", tt.input, expected, evaluated, evaluated)
+			}
+		case string:
+			errObj, ok := evaluated.(*oq_evaluator.Error)
+			if !ok || errObj.Message != expected {
+				t.Errorf("%q - expected error %q, got=%T (%+v)", tt.input, expected, evaluated, evaluated)
+			}
+		}
+	}
+}
+
+func TestNumericLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"0xff + 0b11 + 0o10", 266},
+		{"1_000 * 3", 3000},
+		{"010", 10},
+		{"1.5e3", 1500.0},
+		{"25e-2", 0.25},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case float64:
+			number, ok := evaluated.(*oq_evaluator.Float)
+			if !ok || number.Value != expected {
+				t.Errorf("%q - expected Float %v, got=%T (%+v)", tt.input, expected, evaluated, evaluated)
+			}
+		}
+	}
+}
+
+func TestExactArithmetic(t *testing.T) {
+	tests := []struct {
+		input        string
+		expectedType oq_evaluator.ObjectType
+		expected     string
+	}{
+		{"9223372036854775807 + 1", oq_evaluator.BIGINT_OBJ, "9223372036854775808"},
+		{"-9223372036854775807 - 2", oq_evaluator.BIGINT_OBJ, "-9223372036854775809"},
+		{"4294967296 * 4294967296", oq_evaluator.BIGINT_OBJ, "18446744073709551616"},
+		{"-(-9223372036854775807 - 1)", oq_evaluator.BIGINT_OBJ, "9223372036854775808"},
+		{"(9223372036854775807 + 1) - 1", oq_evaluator.INTEGER_OBJ, "9223372036854775807"},
+		{"100000000000000000000 / 3", oq_evaluator.BIGINT_OBJ, "33333333333333333333"},
+		{"0xFFFFFFFFFFFFFFFF", oq_evaluator.BIGINT_OBJ, "18446744073709551615"},
+		{"100000000000000000000 > 5", oq_evaluator.BOOLEAN_OBJ, "true"},
+		{"100000000000000000000 * 1.5", oq_evaluator.FLOAT_OBJ, "150000000000000000000.0"},
+		{"0.1d + 0.2d == 0.3d", oq_evaluator.BOOLEAN_OBJ, "true"},
+		{"2.50d", oq_evaluator.DECIMAL_OBJ, "2.50"},
+		{"1.10d * 3", oq_evaluator.DECIMAL_OBJ, "3.30"},
+		{"19.99d - 20", oq_evaluator.DECIMAL_OBJ, "-0.01"},
+		{"10.00d / 4", oq_evaluator.DECIMAL_OBJ, "2.50"},
+		{"1d / 3", oq_evaluator.DECIMAL_OBJ, "0.33333333333333333333"},
+		{"2d / 3", oq_evaluator.DECIMAL_OBJ, "0.66666666666666666667"},
+		{"1.5e2d", oq_evaluator.DECIMAL_OBJ, "150"},
+		{"2.50d == 2.5d", oq_evaluator.BOOLEAN_OBJ, "true"},
+		{"100000000000000000000 + 0.5d", oq_evaluator.DECIMAL_OBJ, "100000000000000000000.5"},
+		{"1.5d + 1.0", oq_evaluator.ERROR_OBJ, "type mismatch: DECIMAL + FLOAT"},
+		{"1 / 0", oq_evaluator.ERROR_OBJ, "division by zero"},
+		{"1.5d / 0", oq_evaluator.ERROR_OBJ, "division by zero"},
+		{"1.0 / 0", oq_evaluator.FLOAT_OBJ, "+Inf"},
+		{"[1, 2][100000000000000000000]", oq_evaluator.ERROR_OBJ, "index out of range: 100000000000000000000"},
+		{`"ab"[-100000000000000000000]`, oq_evaluator.ERROR_OBJ, "index out of range: -100000000000000000000"},
+		{"[1, 2, 3][1:100000000000000000000]", oq_evaluator.ARRAY_OBJ, "[2, 3]"},
+		{"for (i in 100000000000000000000) { i }", oq_evaluator.ERROR_OBJ, "loop count too large: 100000000000000000000"},
+		{"for (i in -100000000000000000000) { i }", oq_evaluator.NULL_OBJ, "null"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		actual := evaluated.Inspect()
+		if errObj, ok := evaluated.(*oq_evaluator.Error); ok {
+			actual = errObj.Message
+		}
+		if evaluated.Type() != tt.expectedType || actual != tt.expected {
+			t.Errorf("%q - expected %s %s, got %s %s", tt.input, tt.expectedType, tt.expected, evaluated.Type(), actual)
+		}
+	}
+}
+
+func TestRounding(t *testing.T) {
+	tests := []struct {
+		input        string
+		expectedType oq_evaluator.ObjectType
+		expected     string
+	}{
+		{"round(2.5d)", oq_evaluator.DECIMAL_OBJ, "2"},
+		{"round(3.5d)", oq_evaluator.DECIMAL_OBJ, "4"},
+		{"round(2.345d, 2)", oq_evaluator.DECIMAL_OBJ, "2.34"},
+		{`round(2.345d, 2, "half_up")`, oq_evaluator.DECIMAL_OBJ, "2.35"},
+		{`round(-2.345d, 2, "half_down")`, oq_evaluator.DECIMAL_OBJ, "-2.34"},
+		{`round(2.341d, 2, "up")`, oq_evaluator.DECIMAL_OBJ, "2.35"},
+		{`round(-2.349d, 2, "down")`, oq_evaluator.DECIMAL_OBJ, "-2.34"},
+		{`round(-2.341d, 2, "ceiling")`, oq_evaluator.DECIMAL_OBJ, "-2.34"},
+		{`round(-2.341d, 2, "floor")`, oq_evaluator.DECIMAL_OBJ, "-2.35"},
+		{"round(2.5d, 2)", oq_evaluator.DECIMAL_OBJ, "2.50"},
+		{"round(1250, -2)", oq_evaluator.INTEGER_OBJ, "1200"},
+		{"round(1.25, 1)", oq_evaluator.FLOAT_OBJ, "1.2"},
+		{"decimal(0.1) + 0.2d", oq_evaluator.DECIMAL_OBJ, "0.3"},
+		{"~qzq\nондық(\"1 234,50\")", oq_evaluator.DECIMAL_OBJ, "1234.50"},
+		{"~trk\nyuvarla(2.5d, 0, \"half_up\")", oq_evaluator.DECIMAL_OBJ, "3"},
+		{"format(1234.5d, 2)", oq_evaluator.STRING_OBJ, "1,234.50"},
+		{`round(1d, 0, "nearest")`, oq_evaluator.ERROR_OBJ, "`round` does not know the rounding mode \"nearest\""},
+		{"round()", oq_evaluator.ERROR_OBJ, "wrong number of arguments. got=0, want=1-3"},
+		{`round(1d, 0, "up", 4)`, oq_evaluator.ERROR_OBJ, "wrong number of arguments. got=4, want=1-3"},
+		{`round("1")`, oq_evaluator.ERROR_OBJ, "argument to `round` must be a number, got STRING"},
+		{"round(2.5d, 1000000000)", oq_evaluator.ERROR_OBJ, "`round` cannot use 1000000000 places; the limit is 4096"},
+		{"round(2.5d, -5000)", oq_evaluator.ERROR_OBJ, "`round` cannot use -5000 places; the limit is 4096"},
+		{"format(2.5, 1000000000)", oq_evaluator.ERROR_OBJ, "`format` cannot use 1000000000 places; the limit is 4096"},
+		{`decimal("1e999999999")`, oq_evaluator.ERROR_OBJ, "`decimal` cannot read \"1e999999999\" as a number"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		actual := evaluated.Inspect()
+		if errObj, ok := evaluated.(*oq_evaluator.Error); ok {
+			actual = errObj.Message
+		}
+		if evaluated.Type() != tt.expectedType || actual != tt.expected {
+			t.Errorf("%q - expected %s %s, got %s %s", tt.input, tt.expectedType, tt.expected, evaluated.Type(), actual)
+		}
+	}
+}
+
 func TestStringConcatenation(t *testing.T) {
 	input := `"Hello" + " " + "World!" `
 
@@ -442,6 +955,51 @@ func TestBuiltinFunctions(t *testing.T) {
 		{`len("hello world") `, 11},
 		{`len(1) `, "argument to `len` not supported, got INTEGER"},
 		{`len("one", "two") `, "wrong number of arguments. got=2, want=1"},
+		{`len([1, 2, 3]) `, 3},
+		{`len([]) `, 0},
+		{`ұзындығы([1, 2]) `, 2},
+		{`uzunluk([1]) `, 1},
+		{`first([1, 2, 3]) `, 1},
+		{`first([]) `, nil},
+		{`first(1) `, "argument to `first` must be ARRAY, got INTEGER"},
+		{`бірінші([4, 5]) `, 4},
+		{`ilk([6, 7]) `, 6},
+		{`last([1, 2, 3]) `, 3},
+		{`last([]) `, nil},
+		{`last(1) `, "argument to `last` must be ARRAY, got INTEGER"},
+		{`соңғы([4, 5]) `, 5},
+		{`son([6, 7]) `, 7},
+		{`rest([1, 2, 3]) `, []int{2, 3}},
+		{`rest([]) `, nil},
+		{`қалғаны([1, 2]) `, []int{2}},
+		{`kalan([1, 2, 3]) `, []int{2, 3}},
+		{`push([], 1) `, []int{1}},
+		{`push(1, 1) `, "argument to `push` must be ARRAY, got INTEGER"},
+		{`қосу([1], 2) `, []int{1, 2}},
+		{`ekle([1], 2) `, []int{1, 2}},
+		{"~qzq\nқосу(1, 2) ", "`қосу` аргументі ARRAY болуы керек, INTEGER алынды"},
+		{"~trk\nekle([1]) ", "yanlış sayıda argüman. verilen=1, beklenen=2"},
+		{`len({"a": 1, "b": 2}) `, 2},
+		{`len(keys({"a": 1, "b": 2})) `, 2},
+		{`keys({1: "a", 2: "b"}) `, []int{1, 2}},
+		{`anahtarlar({7: "a"}) `, []int{7}},
+		{`кілттер({8: "a"}) `, []int{8}},
+		{`len(keys({})) `, 0},
+		{`values({"a": 1, "b": 2}) `, []int{1, 2}},
+		{`мәндер({1: 3, 2: 4}) `, []int{3, 4}},
+		{`değerler({true: 5}) `, []int{5}},
+		{`keys([1]) `, "argument to `keys` must be HASH, got ARRAY"},
+		{`has_key({"a": 1}, "a") `, true},
+		{`has_key({"a": 1}, "b") `, false},
+		{`кілті_бар({"a": 1}, "a") `, true},
+		{`anahtar_var({1: 1}, 2) `, false},
+		{`has_key({"a": 1}, [1]) `, "unusable as hash key: ARRAY"},
+		{`values(delete({"a": 1, "b": 2, "c": 3}, "b")) `, []int{1, 3}},
+		{`let h = {"a": 1}
+		delete(h, "a")
+		len(h) `, 1},
+		{`len(жою({"a": 1}, "a")) `, 0},
+		{"~trk\nsil({\"a\": 1}, fn() {}) ", "hash anahtarı olarak kullanılamaz: FUNCTION"},
 	}
 	for _, tt := range tests {
 		evaluated := testEval(tt.input)
@@ -459,6 +1017,397 @@ func TestBuiltinFunctions(t *testing.T) {
 				t.Errorf("wrong error message. expected=%q, got=%q",
 					expected, errObj.Message)
 			}
+		case []int:
+			array, ok := evaluated.(*oq_evaluator.Array)
+			if !ok {
+				t.Errorf("obj not Array. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+
+			if len(array.Elements) != len(expected) {
+				t.Errorf("wrong num of elements. want=%d, got=%d",
+					len(expected), len(array.Elements))
+				continue
+			}
+
+			for i, expectedElem := range expected {
+				testIntegerObject(t, array.Elements[i], int64(expectedElem))
+			}
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case nil:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestArrayLiterals(t *testing.T) {
+	input := "[1, 2 * 2, 3 + 3] "
+
+	evaluated := testEval(input)
+	result, ok := evaluated.(*oq_evaluator.Array)
+	if !ok {
+		t.Fatalf("object is not Array. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	if len(result.Elements) != 3 {
+		t.Fatalf("array has wrong num of elements. got=%d",
+			len(result.Elements))
+	}
+
+	testIntegerObject(t, result.Elements[0], 1)
+	testIntegerObject(t, result.Elements[1], 4)
+	testIntegerObject(t, result.Elements[2], 6)
+
+	if result.Inspect() != "[1, 4, 6]" {
+		t.Errorf("array.Inspect() wrong. got=%q", result.Inspect())
+	}
+}
+
+func TestArrayIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"[1, 2, 3][0] ", 1},
+		{"[1, 2, 3][1] ", 2},
+		{"[1, 2, 3][2] ", 3},
+		{`let i = 0
+		[1][i] `, 1},
+		{"[1, 2, 3][1 + 1] ", 3},
+		{`let myArray = [1, 2, 3]
+		myArray[2] `, 3},
+		{`let myArray = [1, 2, 3]
+		myArray[0] + myArray[1] + myArray[2] `, 6},
+		{"[1, 2, 3][3] ", nil},
+		{"[1, 2, 3][-1] ", nil},
+		{`let sum = 0
+		for (x in [1, 2, 3, 4]) { sum = sum + x }
+		sum `, 10},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		integer, ok := tt.expected.(int)
+		if ok {
+			testIntegerObject(t, evaluated, int64(integer))
+		} else {
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestWhileStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let i = 0
+		while (i < 5) { let i = i + 1 }
+		i `, 5},
+		{`while (false) { 10 }
+		`, nil},
+		{`let countdown = fn(n) {
+			while (true) {
+				if (n < 1) { return 42 }
+				let n = n - 1
+			}
+		}
+		countdown(100000) `, 42},
+		{`~qzq
+		болсын i = 0
+		уақытша (i < 3) { болсын i = i + 1 }
+		i `, 3},
+		{`~trk
+		olsun i = 0
+		iken (i < 3) { olsun i = i + 1 }
+		i `, 3},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		integer, ok := tt.expected.(int)
+		if ok {
+			testIntegerObject(t, evaluated, int64(integer))
+		} else {
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestWhileStatementErrors(t *testing.T) {
+	input := `let i = 0
+	while (i < 5) { i + true }
+	`
+
+	evaluated := testEval(input)
+	errObj, ok := evaluated.(*oq_evaluator.Error)
+	if !ok {
+		t.Fatalf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+	}
+	if errObj.Message != "type mismatch: INTEGER + BOOLEAN" {
+		t.Errorf("wrong error message. got=%q", errObj.Message)
+	}
+}
+
+func TestForStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let find = fn(limit) {
+			for (i in 10) {
+				if (i > limit) { return i }
+			}
+			return -1
+		}
+		find(3) `, 4},
+		{`let find = fn(limit) {
+			for (i in 10) {
+				if (i > limit) { return i }
+			}
+			return -1
+		}
+		find(30) `, -1},
+		{`for (i in 3) { i }
+		`, nil},
+		{`~qzq
+		болсын табу = фн() {
+			үшін (i ішінде 5) {
+				егер (i > 1) { қайтару i }
+			}
+		}
+		табу() `, 2},
+		{`~trk
+		olsun bul = fn() {
+			için (i içinde 5) {
+				eğer (i > 2) { döndür i }
+			}
+		}
+		bul() `, 3},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		integer, ok := tt.expected.(int)
+		if ok {
+			testIntegerObject(t, evaluated, int64(integer))
+		} else {
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestForStatementScope(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`for (i in 3) { let inner = i }
+		inner `, "identifier not found: inner"},
+		{`for (i in 3) { i }
+		i `, "identifier not found: i"},
+		{`for (i in true) { i }
+		`, "not iterable: BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*oq_evaluator.Error)
+		if !ok {
+			t.Errorf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong error message. expected=%q, got=%q",
+				tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestHashLiterals(t *testing.T) {
+	input := `let two = "two"
+	{
+		"one": 10 - 9,
+		two: 1 + 1,
+		"thr" + "ee": 6 / 2,
+		4: 4,
+		true: 5,
+		false: 6
+	}
+	`
+
+	evaluated := testEval(input)
+	result, ok := evaluated.(*oq_evaluator.Hash)
+	if !ok {
+		t.Fatalf("Eval didn't return Hash. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	expected := map[oq_evaluator.HashKey]int64{
+		(&oq_evaluator.String{Value: "one"}).HashKey():   1,
+		(&oq_evaluator.String{Value: "two"}).HashKey():   2,
+		(&oq_evaluator.String{Value: "three"}).HashKey(): 3,
+		(&oq_evaluator.Integer{Value: 4}).HashKey():      4,
+		oq_evaluator.TRUE.HashKey():                      5,
+		oq_evaluator.FALSE.HashKey():                     6,
+	}
+
+	if len(result.Pairs) != len(expected) {
+		t.Fatalf("Hash has wrong num of pairs. got=%d", len(result.Pairs))
+	}
+
+	for expectedKey, expectedValue := range expected {
+		pair, ok := result.Pairs[expectedKey]
+		if !ok {
+			t.Errorf("no pair for given key in Pairs")
+		}
+
+		testIntegerObject(t, pair.Value, expectedValue)
+	}
+
+	if result.Inspect() != "{one: 1, two: 2, three: 3, 4: 4, true: 5, false: 6}" {
+		t.Errorf("hash.Inspect() wrong. got=%q", result.Inspect())
+	}
+}
+
+func TestStringHashKey(t *testing.T) {
+	hello1 := &oq_evaluator.String{Value: "Hello World"}
+	hello2 := &oq_evaluator.String{Value: "Hello World"}
+	diff1 := &oq_evaluator.String{Value: "My name is johnny"}
+	diff2 := &oq_evaluator.String{Value: "My name is johnny"}
+
+	if hello1.HashKey() != hello2.HashKey() {
+		t.Errorf("strings with same content have different hash keys")
+	}
+
+	if diff1.HashKey() != diff2.HashKey() {
+		t.Errorf("strings with same content have different hash keys")
+	}
+
+	if hello1.HashKey() == diff1.HashKey() {
+		t.Errorf("strings with different content have same hash keys")
+	}
+
+	if (&oq_evaluator.Integer{Value: 1}).HashKey() == oq_evaluator.TRUE.HashKey() {
+		t.Errorf("objects of different types have same hash keys")
+	}
+}
+
+func TestHashIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`{"foo": 5}["foo"] `, 5},
+		{`{"foo": 5}["bar"] `, nil},
+		{`let key = "foo"
+		{"foo": 5}[key] `, 5},
+		{`{}["foo"] `, nil},
+		{`{5: 5}[5] `, 5},
+		{`{true: 5}[true] `, 5},
+		{`{false: 5}[false] `, 5},
+		{`let total = 0
+		let prices = {"tea": 2, "bread": 3}
+		for (item in prices) { total = total + prices[item] }
+		total `, 5},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		integer, ok := tt.expected.(int)
+		if ok {
+			testIntegerObject(t, evaluated, int64(integer))
+		} else {
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestErrorPositions(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedLine    int
+		expectedColumn  int
+		expectedInspect string
+	}{
+		{"let a = 1\nlet b = a + true\n", 2, 11, "ERROR: line 2, column 11: type mismatch: INTEGER + BOOLEAN"},
+		{"let a = 1\n\n   missing\n", 3, 4, "ERROR: line 3, column 4: identifier not found: missing"},
+		{"let f = fn(x) {\n  x - \"s\"\n}\nf(1)\n", 2, 5, "ERROR: line 2, column 5: type mismatch: INTEGER - STRING"},
+		{"len(1)\n", 1, 1, "ERROR: line 1, column 1: argument to `len` not supported, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*oq_evaluator.Error)
+		if !ok {
+			t.Errorf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+			continue
+		}
+		if errObj.Position.Line != tt.expectedLine || errObj.Position.Column != tt.expectedColumn {
+			t.Errorf("wrong error position. expected=%d:%d, got=%d:%d",
+				tt.expectedLine, tt.expectedColumn, errObj.Position.Line, errObj.Position.Column)
+		}
+		if errObj.Inspect() != tt.expectedInspect {
+			t.Errorf("wrong error Inspect. expected=%q, got=%q", tt.expectedInspect, errObj.Inspect())
+		}
+	}
+}
+
+func TestScopedBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`len([1, 2])`, 2},
+		{"~qzq\nұзындығы([1, 2])", 2},
+		{"~trk\nuzunluk([1, 2])", 2},
+		{"~qzq\nlen([1, 2])", "identifier not found: len"},
+		{"~trk\nұзындығы([1, 2])", "identifier not found: ұзындығы"},
+		{"uzunluk([1, 2])", "identifier not found: uzunluk"},
+		{"~qzq\nболсын f = фн(x) { бірінші(x) }\n~eng\nf([7])", 7},
+	}
+
+	for _, tt := range tests {
+		l := oq_lexer.New(tt.input)
+		p := oq_parser.New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		env := oq_evaluator.NewEnvironment()
+		env.ScopeBuiltins(true)
+		evaluated := oq_evaluator.Eval(program, env)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*oq_evaluator.Error)
+			if !ok {
+				t.Errorf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+			localized := errObj.Diagnostic().Localize("eng")
+			if localized.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, localized.Message)
+			}
+		}
+	}
+}
+
+func TestSameProgramEvaluatedInEveryDialect(t *testing.T) {
+	for dialect := range dialectWords {
+		l := oq_lexer.New(dialectProgram(dialect))
+		p := oq_parser.New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		// Each program only uses its own dialect's builtin names.
+		env := oq_evaluator.NewEnvironment()
+		env.ScopeBuiltins(true)
+
+		evaluated := oq_evaluator.Eval(program, env)
+		if !testIntegerObject(t, evaluated, 23) {
+			t.Errorf("dialect %s evaluated differently", dialect)
 		}
 	}
 }
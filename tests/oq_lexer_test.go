@@ -1,8 +1,10 @@
 package tests
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/adamerikoff/oq/internal/oq_diagnostic"
 	"github.com/adamerikoff/oq/internal/oq_lexer"
 	"github.com/adamerikoff/oq/internal/oq_token"
 )
@@ -31,6 +33,8 @@ func TestNextToken(t *testing.T) {
 		a
 		"foobar"
 		"foo bar"
+		[1, 2]
+		{"foo": "bar"}
     `
 
 	tests := []struct {
@@ -165,6 +169,20 @@ func TestNextToken(t *testing.T) {
 		{oq_token.STRING, "foo bar"},
 		{oq_token.NEW_LINE, "\n"},
 
+		{oq_token.LBRACKET, "["},
+		{oq_token.INTEGER, "1"},
+		{oq_token.COMMA, ","},
+		{oq_token.INTEGER, "2"},
+		{oq_token.RBRACKET, "]"},
+		{oq_token.NEW_LINE, "\n"},
+
+		{oq_token.LBRACE, "{"},
+		{oq_token.STRING, "foo"},
+		{oq_token.COLON, ":"},
+		{oq_token.STRING, "bar"},
+		{oq_token.RBRACE, "}"},
+		{oq_token.NEW_LINE, "\n"},
+
 		{oq_token.EOF, ""},
 	}
 	l := oq_lexer.New(input)
@@ -181,3 +199,436 @@ func TestNextToken(t *testing.T) {
 		}
 	}
 }
+
+func TestTokenPositions(t *testing.T) {
+	input := "let x = 5\n~qzq\nболсын ат = \"Әлия\"\n  x"
+
+	tests := []struct {
+		expectedType   oq_token.TokenType
+		expectedLine   int
+		expectedColumn int
+		expectedOffset int
+	}{
+		{oq_token.LET, 1, 1, 0},
+		{oq_token.IDENTIFIER, 1, 5, 4},
+		{oq_token.ASSIGN, 1, 7, 6},
+		{oq_token.INTEGER, 1, 9, 8},
+		{oq_token.NEW_LINE, 1, 10, 9},
+		{oq_token.TILDE, 2, 1, 10},
+		{oq_token.IDENTIFIER, 2, 2, 11},
+		{oq_token.NEW_LINE, 2, 5, 14},
+		{oq_token.IDENTIFIER, 3, 1, 15}, // "болсын" is still an identifier, the parser switches dialects
+		{oq_token.IDENTIFIER, 3, 8, 28},
+		{oq_token.ASSIGN, 3, 11, 33},
+		{oq_token.STRING, 3, 13, 35},
+		{oq_token.NEW_LINE, 3, 19, 45},
+		{oq_token.IDENTIFIER, 4, 3, 48},
+		{oq_token.EOF, 4, 4, 49},
+	}
+
+	l := oq_lexer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q (%q)",
+				i, tt.expectedType, tok.Type, tok.Literal)
+		}
+		if tok.Position.Line != tt.expectedLine || tok.Position.Column != tt.expectedColumn {
+			t.Errorf("tests[%d] - position wrong. expected=%d:%d, got=%d:%d",
+				i, tt.expectedLine, tt.expectedColumn, tok.Position.Line, tok.Position.Column)
+		}
+		if tok.Position.Offset != tt.expectedOffset {
+			t.Errorf("tests[%d] - offset wrong. expected=%d, got=%d",
+				i, tt.expectedOffset, tok.Position.Offset)
+		}
+	}
+}
+
+func TestIdentifierAtEndOfInput(t *testing.T) {
+	l := oq_lexer.New("foobar")
+
+	tok := l.NextToken()
+	if tok.Type != oq_token.IDENTIFIER || tok.Literal != "foobar" {
+		t.Fatalf("wrong token. got=%q (%q)", tok.Type, tok.Literal)
+	}
+	if tok := l.NextToken(); tok.Type != oq_token.EOF {
+		t.Fatalf("expected EOF. got=%q (%q)", tok.Type, tok.Literal)
+	}
+}
+
+// dialectWords spells the keywords and builtins of dialectProgram in every
+// built-in dialect.
+var dialectWords = map[string][]string{
+	//       let, fn, true, false, if, elsif, else, return, and, or, for, in, while,
+	//       len, first, last, rest, push, keys, values, has_key, delete
+	"eng": {"let", "fn", "true", "false", "if", "elsif", "else", "return", "and", "or", "for", "in", "while",
+		"len", "first", "last", "rest", "push", "keys", "values", "has_key", "delete"},
+	"qzq": {"болсын", "фн", "шын", "жалған", "егер", "егер_әйтпесе", "әйтпесе", "қайтару", "және", "немесе", "үшін", "ішінде", "уақытша",
+		"ұзындығы", "бірінші", "соңғы", "қалғаны", "қосу", "кілттер", "мәндер", "кілті_бар", "жою"},
+	"trk": {"olsun", "fn", "doğru", "yanlış", "eğer", "yok_eğer", "yoksa", "döndür", "ve", "veya", "için", "içinde", "iken",
+		"uzunluk", "ilk", "son", "kalan", "ekle", "anahtarlar", "değerler", "anahtar_var", "sil"},
+	"rus": {"пусть", "фн", "истина", "ложь", "если", "иначе_если", "иначе", "вернуть", "и", "или", "для", "в", "пока",
+		"длина", "первый", "последний", "остаток", "добавить", "ключи", "значения", "есть_ключ", "удалить"},
+	"uzb": {"boʻlsin", "fn", "rost", "yolgʻon", "agar", "aks_holda_agar", "aks_holda", "qaytar", "va", "yoki", "uchun", "ichida", "toki",
+		"uzunlik", "birinchi", "oxirgi", "qolgani", "qoʻshish", "kalitlar", "qiymatlar", "kalit_bor", "oʻchirish"},
+	"kgz": {"болсун", "фн", "чын", "жалган", "эгер", "эгер_болбосо", "болбосо", "кайтар", "жана", "же", "үчүн", "ичинде", "азырынча",
+		"узундугу", "биринчи", "акыркы", "калганы", "кошуу", "ачкычтар", "маанилер", "ачкычы_бар", "өчүрүү"},
+	"qzl": {"bolsyn", "fn", "şyn", "jalğan", "eger", "eger_äitpese", "äitpese", "qaitaru", "jäne", "nemese", "üşın", "ışınde", "uaqytşa",
+		"ūzyndyğy", "bırınşı", "soñğy", "qalğany", "qosu", "kıltter", "mänder", "kıltı_bar", "joiu"},
+}
+
+// dialectProgram writes the same program in dialect. It evaluates to 23.
+func dialectProgram(dialect string) string {
+	placeholders := []string{"LET", "FN", "TRUE", "FALSE", "IF", "ELSIF", "ELSE", "RETURN", "AND", "OR", "FOR", "IN", "WHILE",
+		"LEN", "FIRST", "LAST", "REST", "PUSH", "KEYS", "VALUES", "HAS_KEY", "DELETE"}
+
+	pairs := []string{"DIALECT", dialect}
+	for i, placeholder := range placeholders {
+		pairs = append(pairs, "$"+placeholder+"$", dialectWords[dialect][i])
+	}
+
+	template := `~DIALECT
+$LET$ xs = [1, 2, 3]
+$LET$ total = 0
+$FOR$ (x $IN$ $PUSH$(xs, 4)) {
+total = total + x
+}
+$LET$ classify = $FN$(n) {
+$IF$ (n > 5 $AND$ $TRUE$) {
+$RETURN$ n
+} $ELSIF$ (n == 0 $OR$ $FALSE$) {
+$RETURN$ 0
+} $ELSE$ {
+$RETURN$ -1
+}
+}
+$WHILE$ (total < 12) {
+total = total + 1
+}
+$LET$ h = {"a": 1, "b": 2}
+$LET$ found = $IF$ ($HAS_KEY$(h, "b")) { 1 } $ELSE$ { 0 }
+classify(total) + $LEN$($REST$(xs)) + $FIRST$(xs) + $LAST$(xs) + $LEN$($KEYS$(h)) + $FIRST$($VALUES$(h)) + $LEN$($KEYS$($DELETE$(h, "a"))) + found
+`
+	return strings.NewReplacer(pairs...).Replace(template)
+}
+
+func TestSameProgramLexedInEveryDialect(t *testing.T) {
+	expected := []oq_token.Token{}
+	l := oq_lexer.New(dialectProgram("eng"))
+	for tok := l.NextToken(); tok.Type != oq_token.EOF; tok = l.NextToken() {
+		expected = append(expected, tok)
+	}
+
+	for dialect := range dialectWords {
+		// Directives are applied by the parser, so switch the lexer by hand.
+		l := oq_lexer.New(dialectProgram(dialect))
+		l.SetDialect(dialect)
+		for i, want := range expected {
+			tok := l.NextToken()
+			if tok.Type != want.Type {
+				t.Fatalf("%s: tokens[%d] - tokentype wrong. expected=%q, got=%q (%q)",
+					dialect, i, want.Type, tok.Type, tok.Literal)
+			}
+			if want.Type != oq_token.IDENTIFIER && tok.Literal != want.Literal {
+				t.Fatalf("%s: tokens[%d] - literal wrong. expected=%q, got=%q",
+					dialect, i, want.Literal, tok.Literal)
+			}
+		}
+		if tok := l.NextToken(); tok.Type != oq_token.EOF {
+			t.Fatalf("%s: expected EOF. got=%q (%q)", dialect, tok.Type, tok.Literal)
+		}
+	}
+}
+
+func TestTokenSpelling(t *testing.T) {
+	input := "болсын ат = шын\n\"a b\" <= 10"
+
+	tests := []struct {
+		expectedType     oq_token.TokenType
+		expectedLiteral  string
+		expectedSpelling string
+	}{
+		{oq_token.LET, "let", "болсын"},
+		{oq_token.IDENTIFIER, "ат", "ат"},
+		{oq_token.ASSIGN, "=", "="},
+		{oq_token.TRUE, "true", "шын"},
+		{oq_token.NEW_LINE, "\n", "\n"},
+		{oq_token.STRING, "a b", "\"a b\""},
+		{oq_token.LESS_EQUAL, "<=", "<="},
+		{oq_token.INTEGER, "10", "10"},
+		{oq_token.EOF, "", ""},
+	}
+
+	l := oq_lexer.New(input)
+	l.SetDialect("qzq")
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q (%q)",
+				i, tt.expectedType, tok.Type, tok.Literal)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Errorf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+		if tok.Spelling != tt.expectedSpelling {
+			t.Errorf("tests[%d] - spelling wrong. expected=%q, got=%q", i, tt.expectedSpelling, tok.Spelling)
+		}
+		if tok.Dialect != "qzq" {
+			t.Errorf("tests[%d] - dialect wrong. expected=%q, got=%q", i, "qzq", tok.Dialect)
+		}
+	}
+}
+
+func TestCommentsAttachedToTokens(t *testing.T) {
+	input := "// sum\nlet x = 10 / 2 /* outer /* inner */ still outer */ + 1 // tail\n~qzq\n/* қайтару */ қайтару x"
+
+	tests := []struct {
+		expectedType     oq_token.TokenType
+		expectedLiteral  string
+		expectedComments []string
+	}{
+		{oq_token.NEW_LINE, "\n", []string{"// sum"}},
+		{oq_token.LET, "let", nil},
+		{oq_token.IDENTIFIER, "x", nil},
+		{oq_token.ASSIGN, "=", nil},
+		{oq_token.INTEGER, "10", nil},
+		{oq_token.SLASH, "/", nil},
+		{oq_token.INTEGER, "2", nil},
+		{oq_token.PLUS, "+", []string{"/* outer /* inner */ still outer */"}},
+		{oq_token.INTEGER, "1", nil},
+		{oq_token.NEW_LINE, "\n", []string{"// tail"}},
+		{oq_token.TILDE, "~", nil},
+		{oq_token.IDENTIFIER, "qzq", nil},
+		{oq_token.NEW_LINE, "\n", nil},
+		{oq_token.RETURN, "return", []string{"/* қайтару */"}},
+		{oq_token.IDENTIFIER, "x", nil},
+		{oq_token.EOF, "", nil},
+	}
+
+	l := oq_lexer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+		if tok.Type == oq_token.IDENTIFIER && tok.Literal == "qzq" {
+			l.SetDialect("qzq")
+		}
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - wrong token. expected=%q %q, got=%q %q",
+				i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+		if len(tok.Comments) != len(tt.expectedComments) {
+			t.Fatalf("tests[%d] - wrong number of comments. expected=%d, got=%d",
+				i, len(tt.expectedComments), len(tok.Comments))
+		}
+		for j, comment := range tok.Comments {
+			if comment.Text != tt.expectedComments[j] {
+				t.Errorf("tests[%d] - comment %d wrong. expected=%q, got=%q",
+					i, j, tt.expectedComments[j], comment.Text)
+			}
+			if input[comment.Position.Offset:comment.End.Offset] != comment.Text {
+				t.Errorf("tests[%d] - comment %d has wrong offsets", i, j)
+			}
+		}
+	}
+
+	if len(l.Diagnostics()) != 0 {
+		t.Errorf("expected no diagnostics, got %v", l.Diagnostics())
+	}
+}
+
+func TestUnterminatedBlockComment(t *testing.T) {
+	l := oq_lexer.New("let x = 1\n/* open /* closed */ still open\n")
+
+	for tok := l.NextToken(); tok.Type != oq_token.EOF; tok = l.NextToken() {
+	}
+
+	diagnostics := l.Diagnostics()
+	if len(diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(diagnostics))
+	}
+	d := diagnostics[0]
+	if d.Code != oq_diagnostic.UNTERMINATED_COMMENT {
+		t.Errorf("wrong code. expected=%s, got=%s", oq_diagnostic.UNTERMINATED_COMMENT, d.Code)
+	}
+	if d.Span.Start.Line != 2 || d.Span.Start.Column != 1 {
+		t.Errorf("wrong span start. expected=2:1, got=%d:%d", d.Span.Start.Line, d.Span.Start.Column)
+	}
+}
+
+func TestRewindRereadsComments(t *testing.T) {
+	l := oq_lexer.New("x /* note */ olsun")
+	l.NextToken()
+	tok := l.NextToken()
+
+	l.SetDialect("trk")
+	l.Rewind(tok)
+	again := l.NextToken()
+
+	if again.Type != oq_token.LET {
+		t.Fatalf("expected LET after rewinding into trk, got=%q", again.Type)
+	}
+	if len(again.Comments) != 1 || again.Comments[0].Text != "/* note */" {
+		t.Errorf("comments not read again. got=%v", again.Comments)
+	}
+}
+
+func TestStringLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"a\"b\\c"`, `a"b\c`},
+		{`"line\nnext\ttab\r\0"`, "line\nnext\ttab\r\x00"},
+		{`"\u0451 \u{451} \u{1F600}"`, "ё ё 😀"},
+		{"`C:\\path\\n \"as is\"`", `C:\path\n "as is"`},
+		{"`two\nlines`", "two\nlines"},
+		{"\"\"\"\n  first\n  \"quoted\" \\u{451}\n\"\"\"", "  first\n  \"quoted\" ё\n"},
+		{`""""""`, ""},
+		{`""`, ""},
+	}
+
+	for _, tt := range tests {
+		l := oq_lexer.New(tt.input)
+		tok := l.NextToken()
+		if tok.Type != oq_token.STRING {
+			t.Fatalf("%q - expected STRING, got=%q", tt.input, tok.Type)
+		}
+		if tok.Literal != tt.expected {
+			t.Errorf("%q - wrong value. expected=%q, got=%q", tt.input, tt.expected, tok.Literal)
+		}
+		if tok.Spelling != tt.input {
+			t.Errorf("%q - wrong spelling. got=%q", tt.input, tok.Spelling)
+		}
+		if next := l.NextToken(); next.Type != oq_token.EOF {
+			t.Errorf("%q - expected EOF after the string, got=%q", tt.input, next.Type)
+		}
+		if len(l.Diagnostics()) != 0 {
+			t.Errorf("%q - unexpected diagnostics: %v", tt.input, l.Diagnostics())
+		}
+	}
+}
+
+func TestStringDiagnostics(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedCode    oq_diagnostic.Code
+		expectedColumn  int
+		expectedEndCol  int
+		expectedMessage string
+	}{
+		{"let s = \"open\nlet t = 1\n", oq_diagnostic.UNTERMINATED_STRING, 9, 14,
+			"unterminated string literal, expected closing \""},
+		{"x = \"\"\"never\nclosed", oq_diagnostic.UNTERMINATED_STRING, 5, 7,
+			"unterminated string literal, expected closing \"\"\""},
+		{"`raw", oq_diagnostic.UNTERMINATED_STRING, 1, 5,
+			"unterminated string literal, expected closing `"},
+		{`"a\qb"`, oq_diagnostic.INVALID_ESCAPE, 3, 5, "invalid escape sequence `\\q`"},
+		{`"\u12"`, oq_diagnostic.INVALID_ESCAPE, 2, 6, "invalid escape sequence `\\u12`"},
+		{`"\u{110000}"`, oq_diagnostic.INVALID_ESCAPE, 2, 12, "invalid escape sequence `\\u{110000}`"},
+	}
+
+	for _, tt := range tests {
+		l := oq_lexer.New(tt.input)
+		for tok := l.NextToken(); tok.Type != oq_token.EOF; tok = l.NextToken() {
+		}
+
+		diagnostics := l.Diagnostics()
+		if len(diagnostics) != 1 {
+			t.Errorf("%q - expected 1 diagnostic, got %d", tt.input, len(diagnostics))
+			continue
+		}
+		d := diagnostics[0]
+		if d.Code != tt.expectedCode {
+			t.Errorf("%q - wrong code. expected=%s, got=%s", tt.input, tt.expectedCode, d.Code)
+		}
+		if d.Span.Start.Column != tt.expectedColumn || d.Span.End.Column != tt.expectedEndCol {
+			t.Errorf("%q - wrong span. expected=%d-%d, got=%d-%d", tt.input,
+				tt.expectedColumn, tt.expectedEndCol, d.Span.Start.Column, d.Span.End.Column)
+		}
+		if d.Message != tt.expectedMessage {
+			t.Errorf("%q - wrong message. expected=%q, got=%q", tt.input, tt.expectedMessage, d.Message)
+		}
+	}
+}
+
+func TestNumberLiterals(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedType    oq_token.TokenType
+		expectedLiteral string
+	}{
+		{"42", oq_token.INTEGER, "42"},
+		{"1_000_000", oq_token.INTEGER, "1000000"},
+		{"0755", oq_token.INTEGER, "0755"},
+		{"0xFF", oq_token.INTEGER, "0xFF"},
+		{"0XdEaD_bEeF", oq_token.INTEGER, "0xdEaDbEeF"},
+		{"0b1010_1010", oq_token.INTEGER, "0b10101010"},
+		{"0o17", oq_token.INTEGER, "0o17"},
+		{"3.14", oq_token.FLOAT, "3.14"},
+		{"1.5e-3", oq_token.FLOAT, "1.5e-3"},
+		{"2E10", oq_token.FLOAT, "2e10"},
+		{"6.022_140e+23", oq_token.FLOAT, "6.022140e+23"},
+		{"2.50d", oq_token.DECIMAL, "2.50d"},
+		{"1_000D", oq_token.DECIMAL, "1000d"},
+		{"1.5e2d", oq_token.DECIMAL, "1.5e2d"},
+	}
+
+	for _, tt := range tests {
+		l := oq_lexer.New(tt.input)
+		tok := l.NextToken()
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Errorf("%q - expected %s %q, got %s %q", tt.input, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+		if tok.Spelling != tt.input {
+			t.Errorf("%q - wrong spelling. got=%q", tt.input, tok.Spelling)
+		}
+		if next := l.NextToken(); next.Type != oq_token.EOF {
+			t.Errorf("%q - expected EOF after the number, got=%q", tt.input, next.Type)
+		}
+		if len(l.Diagnostics()) != 0 {
+			t.Errorf("%q - unexpected diagnostics: %v", tt.input, l.Diagnostics())
+		}
+	}
+}
+
+func TestMalformedNumbers(t *testing.T) {
+	tests := []struct {
+		input        string
+		expectedCode oq_diagnostic.Code
+		spelling     string
+	}{
+		{"1.2.3 + 1", oq_diagnostic.MALFORMED_NUMBER, "1.2.3"},
+		{"1.e5 + 1", oq_diagnostic.MALFORMED_NUMBER, "1.e5"},
+		{"1..2 + 1", oq_diagnostic.MALFORMED_NUMBER, "1..2"},
+		{"3. + 1", oq_diagnostic.MALFORMED_NUMBER, "3."},
+		{"0xZZ + 1", oq_diagnostic.MALFORMED_NUMBER, "0xZZ"},
+		{"0x + 1", oq_diagnostic.MALFORMED_NUMBER, "0x"},
+		{"0b102 + 1", oq_diagnostic.MALFORMED_NUMBER, "0b102"},
+		{"12abc + 1", oq_diagnostic.MALFORMED_NUMBER, "12abc"},
+		{"1e + 1", oq_diagnostic.MALFORMED_NUMBER, "1e"},
+		{"1__000 + 1", oq_diagnostic.MALFORMED_NUMBER, "1__000"},
+		{"100_ + 1", oq_diagnostic.MALFORMED_NUMBER, "100_"},
+		{"0x_FF + 1", oq_diagnostic.MALFORMED_NUMBER, "0x_FF"},
+		{"2.5dd + 1", oq_diagnostic.MALFORMED_NUMBER, "2.5dd"},
+		{"١٢٣ + 1", oq_diagnostic.NON_ASCII_DIGIT, "١٢٣"},
+		{"12٣ + 1", oq_diagnostic.NON_ASCII_DIGIT, "12٣"},
+	}
+
+	for _, tt := range tests {
+		l := oq_lexer.New(tt.input)
+		tok := l.NextToken()
+		if tok.Type != oq_token.ILLEGAL || tok.Spelling != tt.spelling {
+			t.Errorf("%q - expected ILLEGAL %q, got %s %q", tt.input, tt.spelling, tok.Type, tok.Spelling)
+		}
+		if next := l.NextToken(); next.Type != oq_token.PLUS {
+			t.Errorf("%q - expected the number to end before '+', got=%q", tt.input, next.Type)
+		}
+
+		diagnostics := l.Diagnostics()
+		if len(diagnostics) != 1 || diagnostics[0].Code != tt.expectedCode {
+			t.Errorf("%q - expected one %s diagnostic, got=%v", tt.input, tt.expectedCode, diagnostics)
+		}
+	}
+}
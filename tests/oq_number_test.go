@@ -0,0 +1,39 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/adamerikoff/oq/internal/oq_number"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input            string
+		expectedUnscaled string
+		expectedScale    int
+	}{
+		{"2.50", "250", 2},
+		{"-12.5", "-125", 1},
+		{"1.5e2", "150", 0},
+		{"1.5e-3", "15", 4},
+		{"7", "7", 0},
+	}
+
+	for _, tt := range tests {
+		unscaled, scale, ok := oq_number.ParseDecimal(tt.input)
+		if !ok {
+			t.Errorf("%q - expected a decimal", tt.input)
+			continue
+		}
+		if unscaled.String() != tt.expectedUnscaled || scale != tt.expectedScale {
+			t.Errorf("%q - expected %s with scale %d, got %s with scale %d",
+				tt.input, tt.expectedUnscaled, tt.expectedScale, unscaled, scale)
+		}
+	}
+
+	for _, input := range []string{"", "1.2.3", "1.-5", "abc", "1e99999999999999999999", "1.5e-5000"} {
+		if _, _, ok := oq_number.ParseDecimal(input); ok {
+			t.Errorf("%q - expected to be rejected", input)
+		}
+	}
+}
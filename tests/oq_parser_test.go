@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/adamerikoff/oq/internal/oq_ast"
+	"github.com/adamerikoff/oq/internal/oq_diagnostic"
 	"github.com/adamerikoff/oq/internal/oq_lexer"
 	"github.com/adamerikoff/oq/internal/oq_parser"
 	"github.com/adamerikoff/oq/internal/oq_token"
@@ -435,11 +436,17 @@ func TestParsingInfixExpressions(t *testing.T) {
 		{"5 / 5 ", 5, "/", 5},
 		{"5 > 5 ", 5, ">", 5},
 		{"5 < 5 ", 5, "<", 5},
+		{"5 <= 5 ", 5, "<=", 5},
+		{"5 >= 5 ", 5, ">=", 5},
 		{"5 == 5 ", 5, "==", 5},
 		{"5 != 5 ", 5, "!=", 5},
 		{"true == true ", true, "==", true},
 		{"true != false ", true, "!=", false},
 		{"false == false ", false, "==", false},
+		{"true and false ", true, "and", false},
+		{"true or false ", true, "or", false},
+		{"~qzq\nшын және жалған ", true, "and", false},
+		{"~trk\ndoğru veya yanlış ", true, "or", false},
 	}
 
 	for _, tt := range infixTests {
@@ -583,6 +590,46 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"add(a + b + c * d / f + g)",
 			"add((((a + b) + ((c * d) / f)) + g))",
 		},
+		{
+			"5 >= 4 == 3 <= 4 ",
+			"((5 >= 4) == (3 <= 4))",
+		},
+		{
+			"a + 1 <= b * 2 ",
+			"((a + 1) <= (b * 2))",
+		},
+		{
+			"a * [1, 2, 3, 4][b * c] * d ",
+			"((a * ([1, 2, 3, 4][(b * c)])) * d)",
+		},
+		{
+			"add(a * b[2], b[1], 2 * [1, 2][1]) ",
+			"add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))",
+		},
+		{
+			"a = b = c + 1 ",
+			"a = b = (c + 1)",
+		},
+		{
+			"x = a or b ",
+			"x = (a or b)",
+		},
+		{
+			"a or b and c ",
+			"(a or (b and c))",
+		},
+		{
+			"a and b or c and d ",
+			"((a and b) or (c and d))",
+		},
+		{
+			"a < b and c == d ",
+			"((a < b) and (c == d))",
+		},
+		{
+			"!a or b ",
+			"((!a) or b)",
+		},
 	}
 	for _, tt := range tests {
 		l := oq_lexer.New(tt.input)
@@ -623,18 +670,22 @@ func TestIfExpression(t *testing.T) {
 		t.Fatalf("stmt.Expression is not oq_ast.IfExpression. got=%T",
 			stmt.Expression)
 	}
-	if !testInfixExpression(t, exp.Condition, "x", "<", "y") {
+	if len(exp.Branches) != 1 {
+		t.Fatalf("exp.Branches does not contain 1 branch. got=%d", len(exp.Branches))
+	}
+	branch := exp.Branches[0]
+	if !testInfixExpression(t, branch.Condition, "x", "<", "y") {
 		return
 	}
-	if len(exp.Consequence.Statements) != 1 {
+	if len(branch.Consequence.Statements) != 1 {
 		t.Errorf("consequence is not 1 statements. got=%d\n",
-			len(exp.Consequence.Statements))
+			len(branch.Consequence.Statements))
 	}
 
-	consequence, ok := exp.Consequence.Statements[0].(*oq_ast.ExpressionStatement)
+	consequence, ok := branch.Consequence.Statements[0].(*oq_ast.ExpressionStatement)
 	if !ok {
 		t.Fatalf("Statements[0] is not oq_ast.ExpressionStatement. got=%T",
-			exp.Consequence.Statements[0])
+			branch.Consequence.Statements[0])
 	}
 	if !testIdentifier(t, consequence.Expression, "x") {
 		return
@@ -644,6 +695,52 @@ func TestIfExpression(t *testing.T) {
 	}
 }
 
+func TestIfElsifExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"if (x < y) { x } elsif (x > y) { y } else { z }\n",
+			"if(x < y) xelsif(x > y) yelse z",
+		},
+		{
+			"if (a) { 1 } elsif (b) { 2 } elsif (c) { 3 }\n",
+			"ifa 1elsifb 2elsifc 3",
+		},
+		{
+			"~qzq\nегер (a) { 1 } егер_әйтпесе (b) { 2 } әйтпесе { 3 }\n",
+			"ifa 1elsifb 2else 3",
+		},
+		{
+			"~trk\neğer (a) { 1 } yok_eğer (b) { 2 } yoksa { 3 }\n",
+			"ifa 1elsifb 2else 3",
+		},
+	}
+
+	for _, tt := range tests {
+		l := oq_lexer.New(tt.input)
+		p := oq_parser.New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+				1, len(program.Statements))
+		}
+
+		stmt := program.Statements[0].(*oq_ast.ExpressionStatement)
+		if _, ok := stmt.Expression.(*oq_ast.IfExpression); !ok {
+			t.Fatalf("stmt.Expression is not oq_ast.IfExpression. got=%T",
+				stmt.Expression)
+		}
+		if program.String() != tt.expected {
+			t.Errorf("program.String() wrong. expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
 func TestFunctionLiteralParsing(t *testing.T) {
 	input := `fn(x, y) { x + y }`
 
@@ -943,3 +1040,491 @@ func TestStringLiteralExpression(t *testing.T) {
 		t.Errorf("literal.Value not %q. got=%q", "hello world", literal.Value)
 	}
 }
+
+func TestWhileStatementParsing(t *testing.T) {
+	tests := []struct {
+		input string
+	}{
+		{"while (x < y) { x }\n"},
+		{"~qzq\nуақытша (x < y) { x }\n"},
+		{"~trk\niken (x < y) { x }\n"},
+	}
+
+	for _, tt := range tests {
+		l := oq_lexer.New(tt.input)
+		p := oq_parser.New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+				1, len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*oq_ast.WhileStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not oq_ast.WhileStatement. got=%T",
+				program.Statements[0])
+		}
+		if stmt.TokenLiteral() != "while" {
+			t.Errorf("stmt.TokenLiteral not 'while'. got=%q", stmt.TokenLiteral())
+		}
+		if !testInfixExpression(t, stmt.Condition, "x", "<", "y") {
+			return
+		}
+		if len(stmt.Body.Statements) != 1 {
+			t.Fatalf("body is not 1 statements. got=%d\n", len(stmt.Body.Statements))
+		}
+
+		body, ok := stmt.Body.Statements[0].(*oq_ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("Statements[0] is not oq_ast.ExpressionStatement. got=%T",
+				stmt.Body.Statements[0])
+		}
+		testIdentifier(t, body.Expression, "x")
+	}
+}
+
+func TestForStatementParsing(t *testing.T) {
+	tests := []struct {
+		input string
+	}{
+		{"for (i in n) { i }\n"},
+		{"~qzq\nүшін (i ішінде n) { i }\n"},
+		{"~trk\niçin (i içinde n) { i }\n"},
+	}
+
+	for _, tt := range tests {
+		l := oq_lexer.New(tt.input)
+		p := oq_parser.New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+				1, len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*oq_ast.ForStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not oq_ast.ForStatement. got=%T",
+				program.Statements[0])
+		}
+		if stmt.TokenLiteral() != "for" {
+			t.Errorf("stmt.TokenLiteral not 'for'. got=%q", stmt.TokenLiteral())
+		}
+		if !testIdentifier(t, stmt.Variable, "i") {
+			return
+		}
+		if !testIdentifier(t, stmt.Iterable, "n") {
+			return
+		}
+		if stmt.String() != "for(i in n) i" {
+			t.Errorf("stmt.String() wrong. got=%q", stmt.String())
+		}
+	}
+}
+
+func TestAssignExpressionParsing(t *testing.T) {
+	input := "x = 5 * y "
+
+	l := oq_lexer.New(input)
+	p := oq_parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+			1, len(program.Statements))
+	}
+
+	stmt := program.Statements[0].(*oq_ast.ExpressionStatement)
+	exp, ok := stmt.Expression.(*oq_ast.AssignExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not oq_ast.AssignExpression. got=%T", stmt.Expression)
+	}
+	if !testIdentifier(t, exp.Name, "x") {
+		return
+	}
+	testInfixExpression(t, exp.Value, 5, "*", "y")
+}
+
+func TestInvalidAssignmentTarget(t *testing.T) {
+	input := "1 + x = 5 "
+
+	l := oq_lexer.New(input)
+	p := oq_parser.New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) != 1 {
+		t.Fatalf("expected 1 parser error. got=%d (%v)", len(errors), errors)
+	}
+	if errors[0].String() != "line 1, column 7: cannot assign to (1 + x)" {
+		t.Errorf("wrong parser error. got=%q", errors[0])
+	}
+}
+
+func TestParsingArrayLiterals(t *testing.T) {
+	input := "[1, 2 * 2, 3 + 3] "
+
+	l := oq_lexer.New(input)
+	p := oq_parser.New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt, ok := program.Statements[0].(*oq_ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not oq_ast.ExpressionStatement. got=%T",
+			program.Statements[0])
+	}
+	array, ok := stmt.Expression.(*oq_ast.ArrayLiteral)
+	if !ok {
+		t.Fatalf("exp not oq_ast.ArrayLiteral. got=%T", stmt.Expression)
+	}
+
+	if len(array.Elements) != 3 {
+		t.Fatalf("len(array.Elements) not 3. got=%d", len(array.Elements))
+	}
+
+	testIntegerLiteral(t, array.Elements[0], 1)
+	testInfixExpression(t, array.Elements[1], 2, "*", 2)
+	testInfixExpression(t, array.Elements[2], 3, "+", 3)
+}
+
+func TestParsingIndexExpressions(t *testing.T) {
+	input := "myArray[1 + 1] "
+
+	l := oq_lexer.New(input)
+	p := oq_parser.New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt, ok := program.Statements[0].(*oq_ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not oq_ast.ExpressionStatement. got=%T",
+			program.Statements[0])
+	}
+	indexExp, ok := stmt.Expression.(*oq_ast.IndexExpression)
+	if !ok {
+		t.Fatalf("exp not *oq_ast.IndexExpression. got=%T", stmt.Expression)
+	}
+
+	if !testIdentifier(t, indexExp.Left, "myArray") {
+		return
+	}
+
+	if !testInfixExpression(t, indexExp.Index, 1, "+", 1) {
+		return
+	}
+}
+
+func TestParsingSliceExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"s[1:3]", "(s[1:3])"},
+		{"s[:n + 1]", "(s[:(n + 1)])"},
+		{"s[2:]", "(s[2:])"},
+		{"s[:]", "(s[:])"},
+		{"[1, 2, 3][1:][0]", "(([1, 2, 3][1:])[0])"},
+		{"h[{\"a\": 1}[\"a\"]:]", "(h[({a: 1}[a]):])"},
+	}
+
+	for _, tt := range tests {
+		l := oq_lexer.New(tt.input)
+		p := oq_parser.New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("program.String() wrong. expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestParsingHashLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"one": 1, "two": 2, "three": 3} `, "{one: 1, two: 2, three: 3}"},
+		{"{} ", "{}"},
+		{`{"one": 0 + 1, "two": 10 - 8, "three": 15 / 5} `, "{one: (0 + 1), two: (10 - 8), three: (15 / 5)}"},
+		{`{1: true, false: "no"} `, "{1: true, false: no}"},
+		{`{
+			"one": 1,
+			"two": 2
+		}
+		`, "{one: 1, two: 2}"},
+		{`let h = {"a": 1}
+		`, "let h = {a: 1}"},
+		{`if (x) { {"a": 1} } `, "ifx {a: 1}"},
+		{`h["a"] `, "(h[a])"},
+	}
+
+	for _, tt := range tests {
+		l := oq_lexer.New(tt.input)
+		p := oq_parser.New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("program.String() wrong. expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestParsingHashLiteralPairs(t *testing.T) {
+	input := `{"one": 1, "two": 2, "three": 3} `
+
+	l := oq_lexer.New(input)
+	p := oq_parser.New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*oq_ast.ExpressionStatement)
+	hash, ok := stmt.Expression.(*oq_ast.HashLiteral)
+	if !ok {
+		t.Fatalf("exp is not oq_ast.HashLiteral. got=%T", stmt.Expression)
+	}
+
+	expected := []struct {
+		key   string
+		value int64
+	}{
+		{"one", 1},
+		{"two", 2},
+		{"three", 3},
+	}
+
+	if len(hash.Pairs) != len(expected) {
+		t.Fatalf("hash.Pairs has wrong length. got=%d", len(hash.Pairs))
+	}
+
+	for i, pair := range hash.Pairs {
+		literal, ok := pair.Key.(*oq_ast.StringLiteral)
+		if !ok {
+			t.Errorf("key is not oq_ast.StringLiteral. got=%T", pair.Key)
+			continue
+		}
+		if literal.Value != expected[i].key {
+			t.Errorf("key %d wrong. expected=%q, got=%q", i, expected[i].key, literal.Value)
+		}
+		testIntegerLiteral(t, pair.Value, expected[i].value)
+	}
+}
+
+func TestParserErrorPositions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x = 5\nlet = 10\n", "line 2, column 5: expected next token to be IDENTIFIER, got = instead"},
+		{"let x = 5\n  if (x { x }\n", "line 2, column 9: expected next token to be ), got { instead"},
+		{"add(1,\n", "line 1, column 7: no prefix parse function for NEW_LINE found"},
+		{"~xyz\n", "line 1, column 2: unknown dialect 'xyz'"},
+	}
+
+	for _, tt := range tests {
+		l := oq_lexer.New(tt.input)
+		p := oq_parser.New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("expected parser errors for %q", tt.input)
+			continue
+		}
+		if errors[0].String() != tt.expected {
+			t.Errorf("wrong first parser error. expected=%q, got=%q", tt.expected, errors[0])
+		}
+	}
+}
+
+func TestNodesRecordSpellingAndDialect(t *testing.T) {
+	input := `let a = 1
+~qzq
+болсын б = шын
+~trk
+olsun c = yanlış
+`
+	l := oq_lexer.New(input)
+	p := oq_parser.New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	tests := []struct {
+		expectedDialect  string
+		expectedSpelling string
+		expectedBoolean  string
+	}{
+		{"eng", "let", ""},
+		{"qzq", "болсын", "шын"},
+		{"trk", "olsun", "yanlış"},
+	}
+
+	if len(program.Statements) != len(tests) {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d", len(tests), len(program.Statements))
+	}
+
+	for i, tt := range tests {
+		stmt, ok := program.Statements[i].(*oq_ast.LetStatement)
+		if !ok {
+			t.Fatalf("program.Statements[%d] is not *oq_ast.LetStatement. got=%T", i, program.Statements[i])
+		}
+		if stmt.TokenLiteral() != "let" {
+			t.Errorf("stmt.TokenLiteral not 'let'. got=%q", stmt.TokenLiteral())
+		}
+		if stmt.Token.Spelling != tt.expectedSpelling {
+			t.Errorf("stmt.Token.Spelling wrong. expected=%q, got=%q", tt.expectedSpelling, stmt.Token.Spelling)
+		}
+		if stmt.Dialect() != tt.expectedDialect || stmt.Value.Dialect() != tt.expectedDialect {
+			t.Errorf("dialect wrong. expected=%q, got=%q and %q", tt.expectedDialect, stmt.Dialect(), stmt.Value.Dialect())
+		}
+		if boolean, ok := stmt.Value.(*oq_ast.Boolean); ok && boolean.Token.Spelling != tt.expectedBoolean {
+			t.Errorf("boolean spelling wrong. expected=%q, got=%q", tt.expectedBoolean, boolean.Token.Spelling)
+		}
+	}
+}
+
+func TestAssignmentErrorQuotesSourceSpelling(t *testing.T) {
+	l := oq_lexer.New("~qzq\nшын = 1\n")
+	p := oq_parser.New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) != 1 {
+		t.Fatalf("expected 1 parser error. got=%d (%v)", len(errors), errors)
+	}
+	if errors[0].Message != "шын мәнін тағайындау мүмкін емес" {
+		t.Errorf("wrong parser error. got=%q", errors[0].Message)
+	}
+}
+
+func TestBlockScopedDialectDirectives(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let f = fn() {\n~qzq\nболсын а = 1\n}\nlet b = 2\n", "let f = fn() let а = 1let b = 2"},
+		{"if (true) {\n~trk\nolsun x = 1\n} else { 2 }\n", "iftrue let x = 1else 2"},
+		{"~trk\neğer (doğru) {\n~qzq\nболсын а = 1\n} yoksa { 2 }\nolsun c = 3\n", "iftrue let а = 1else 2let c = 3"},
+		{"if (true) {\n~qzq\nегер (шын) { ~trk\n1 }\nболсын а = 2\n}\n", "iftrue iftrue 1let а = 2"},
+	}
+
+	for _, tt := range tests {
+		l := oq_lexer.New(tt.input)
+		p := oq_parser.New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("program.String() wrong. expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestDirectiveDoesNotLeakOutOfBlock(t *testing.T) {
+	l := oq_lexer.New("if (true) {\n~trk\n1\n}\nolsun\n")
+	p := oq_parser.New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("program.Statements does not contain 2 statements. got=%d", len(program.Statements))
+	}
+	stmt, ok := program.Statements[1].(*oq_ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[1] is not *oq_ast.ExpressionStatement. got=%T", program.Statements[1])
+	}
+	// Outside the block `olsun` is no longer the Turkish `let`.
+	testIdentifier(t, stmt.Expression, "olsun")
+	if stmt.Dialect() != "eng" {
+		t.Errorf("stmt.Dialect() wrong. expected=%q, got=%q", "eng", stmt.Dialect())
+	}
+}
+
+func TestInterpolatedStringParsing(t *testing.T) {
+	l := oq_lexer.New(`"sum: ${a + b * 2}, done"`)
+	p := oq_parser.New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	statement := program.Statements[0].(*oq_ast.ExpressionStatement)
+	interpolated, ok := statement.Expression.(*oq_ast.InterpolatedString)
+	if !ok {
+		t.Fatalf("expression is not InterpolatedString. got=%T", statement.Expression)
+	}
+	if len(interpolated.Parts) != 3 {
+		t.Fatalf("wrong number of parts. expected=3, got=%d", len(interpolated.Parts))
+	}
+	if text, ok := interpolated.Parts[0].(*oq_ast.StringLiteral); !ok || text.Value != "sum: " {
+		t.Errorf("first part wrong. got=%q", interpolated.Parts[0].String())
+	}
+	if interpolated.Parts[1].String() != "(a + (b * 2))" {
+		t.Errorf("embedded expression wrong. got=%q", interpolated.Parts[1].String())
+	}
+	if interpolated.Parts[1].Pos().Column != 11 { // the '+'
+		t.Errorf("embedded expression position wrong. expected column 11, got=%d", interpolated.Parts[1].Pos().Column)
+	}
+
+	tests := []struct {
+		input        string
+		expectedCode oq_diagnostic.Code
+	}{
+		{`"${}"`, oq_diagnostic.EMPTY_INTERPOLATION},
+		{`"${a b}"`, oq_diagnostic.UNEXPECTED_TOKEN},
+		{`"${a"`, oq_diagnostic.UNTERMINATED_INTERPOLATION},
+		{`"${"\q"}"`, oq_diagnostic.INVALID_ESCAPE},
+	}
+
+	for _, tt := range tests {
+		p := oq_parser.New(oq_lexer.New(tt.input))
+		p.ParseProgram()
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0].Code != tt.expectedCode {
+			t.Errorf("%q - expected %s, got=%v", tt.input, tt.expectedCode, errors)
+		}
+	}
+}
+
+func TestParsingDecimalLiterals(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedValue string
+	}{
+		{"2.50d", "2.50"},
+		{"1.5e2d", "1.5e2"},
+		{"1.5e-3d", "1.5e-3"},
+	}
+
+	for _, tt := range tests {
+		p := oq_parser.New(oq_lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*oq_ast.ExpressionStatement)
+		literal, ok := stmt.Expression.(*oq_ast.DecimalLiteral)
+		if !ok {
+			t.Fatalf("%q - expression is not *oq_ast.DecimalLiteral. got=%T", tt.input, stmt.Expression)
+		}
+		if literal.Value != tt.expectedValue {
+			t.Errorf("%q - expected value %q, got %q", tt.input, tt.expectedValue, literal.Value)
+		}
+	}
+
+	// Out of range for an int, and in range but too large to compute with.
+	for _, input := range []string{"1e99999999999999999999d", "1e999999999d", "1.5e-5000d"} {
+		p := oq_parser.New(oq_lexer.New(input))
+		p.ParseProgram()
+		errors := p.Errors()
+		if len(errors) != 1 || errors[0].Code != oq_diagnostic.INVALID_DECIMAL {
+			t.Errorf("%q - expected %s, got=%v", input, oq_diagnostic.INVALID_DECIMAL, errors)
+		}
+	}
+}
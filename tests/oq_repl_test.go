@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/adamerikoff/oq/internal/oq_repl"
+)
+
+func TestReplKeepsDialectBetweenInputs(t *testing.T) {
+	input := "~qzq\nболсын а = шын\nа\n~trk\nolsun b = yanlış\nb\n"
+	var out bytes.Buffer
+
+	oq_repl.Start(strings.NewReader(input), &out)
+
+	expected := "true\nfalse\n"
+	if out.String() != expected {
+		t.Errorf("wrong output. expected=%q, got=%q", expected, out.String())
+	}
+}
+
+func TestReplShowsLocalNumbers(t *testing.T) {
+	input := "1234.5\n~trk\n[1234.5, 7]\n~qzq\n{\"сан\": 1000000}\n"
+	var out bytes.Buffer
+
+	oq_repl.StartWithOptions(strings.NewReader(input), &out, oq_repl.Options{LocalNumbers: true})
+
+	expected := "1,234.5\n[1.234,5; 7]\n{сан: 1\u00a0000\u00a0000}\n"
+	if out.String() != expected {
+		t.Errorf("wrong output. expected=%q, got=%q", expected, out.String())
+	}
+}
+
+func TestReplAppliesLangAndScopedBuiltins(t *testing.T) {
+	input := "~qzq\nұзындығы(\"abc\")\nlen(\"abc\")\n"
+	var out bytes.Buffer
+
+	oq_repl.StartWithOptions(strings.NewReader(input), &out, oq_repl.Options{Lang: "rus", ScopedBuiltins: true})
+
+	if !strings.HasPrefix(out.String(), "3\n") {
+		t.Errorf("the qzq builtin should still work. got=%q", out.String())
+	}
+	if !strings.Contains(out.String(), "идентификатор не найден: len") {
+		t.Errorf("expected the scoping error in Russian. got=%q", out.String())
+	}
+}
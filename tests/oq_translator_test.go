@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/adamerikoff/oq/internal/oq_translator"
+)
+
+func TestTranslate(t *testing.T) {
+	input := `~qzq
+болсын   сан =  1
+    ~trk
+eğer (san > 0 ve doğru) { döndür uzunluk("әлия") } yoksa { döndür ilk([1]) }
+`
+	expected := `~eng
+let   сан =  1
+if (san > 0 and true) { return len("әлия") } else { return first([1]) }
+`
+
+	got, err := oq_translator.Translate(input, "eng")
+	if err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	if got != expected {
+		t.Errorf("wrong translation.\nexpected=\n%s\ngot=\n%s", expected, got)
+	}
+}
+
+func TestTranslatedProgramsEvaluateTheSame(t *testing.T) {
+	source := dialectProgram("qzq")
+
+	for dialect := range dialectWords {
+		translated, err := oq_translator.Translate(source, dialect)
+		if err != nil {
+			t.Fatalf("Translate to %s failed: %v", dialect, err)
+		}
+		if translated != dialectProgram(dialect) {
+			t.Errorf("translation to %s differs from the hand-written program.\ngot=\n%s", dialect, translated)
+		}
+		testIntegerObject(t, testEval(translated), 23)
+	}
+}
+
+func TestTranslateErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		target        string
+		expectedError string
+	}{
+		{"let ve = 1\n", "trk", `identifier "ve" is a keyword in trk`},
+		{"let son = 1\nlast([son])\n", "trk", `builtin "last" would become "son", which the program declares itself`},
+		{"~klingon\n", "eng", "unknown dialect 'klingon'"},
+		{"let x = 1\n", "klingon", "unknown dialect 'klingon'"},
+		{"let x = 1.2.3\n", "trk", `line 1, column 9: malformed number "1.2.3"`},
+		{"let x = @\n", "trk", `line 1, column 9: illegal character "@"`},
+	}
+
+	for _, tt := range tests {
+		_, err := oq_translator.Translate(tt.input, tt.target)
+		if err == nil {
+			t.Errorf("expected error for %q", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("wrong error. expected to contain %q, got=%q", tt.expectedError, err.Error())
+		}
+	}
+}
+
+func TestTranslateKeepsShadowedBuiltins(t *testing.T) {
+	input := "let len = fn(x) { 7 }\nlen([1])\n"
+
+	got, err := oq_translator.Translate(input, "trk")
+	if err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	if got != "~trk\nolsun len = fn(x) { 7 }\nlen([1])\n" {
+		t.Errorf("wrong translation. got=%q", got)
+	}
+}
+
+func TestTranslateBlockScopedDirective(t *testing.T) {
+	input := "if (true) {\n    ~trk\n    olsun x = 1\n}\nlet y = 2\n"
+	expected := "~qzq\nегер (шын) {\n    болсын x = 1\n}\nболсын y = 2\n"
+
+	got, err := oq_translator.Translate(input, "qzq")
+	if err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	if got != expected {
+		t.Errorf("wrong translation.\nexpected=%q\ngot=%q", expected, got)
+	}
+}
+
+func TestTranslateKeepsComments(t *testing.T) {
+	input := "// болсын stays\nlet x = 1 /* if /* else */ fn */\nreturn x // len\n"
+	expected := "~trk\n// болсын stays\nolsun x = 1 /* if /* else */ fn */\ndöndür x // len\n"
+
+	got, err := oq_translator.Translate(input, "trk")
+	if err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	if got != expected {
+		t.Errorf("wrong translation.\nexpected=%q\ngot=%q", expected, got)
+	}
+
+	if _, err := oq_translator.Translate("let x = 1 /* open", "trk"); err == nil {
+		t.Errorf("expected an error for an unterminated comment")
+	}
+}
+
+func TestTranslateInterpolatedStrings(t *testing.T) {
+	input := "~qzq\nболсын х = \"${ұзындығы([1])} ${шын} ұзындығы\"\n"
+	expected := "~eng\nlet х = \"${len([1])} ${true} ұзындығы\"\n"
+
+	got, err := oq_translator.Translate(input, "eng")
+	if err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	if got != expected {
+		t.Errorf("wrong translation.\nexpected=%q\ngot=%q", expected, got)
+	}
+}
+
+func TestTranslateInlineDirective(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"/* note */ ~trk // c\nolsun x = 1\n", "~eng\n/* note */ // c\nlet x = 1\n"},
+		{"if (true) { ~trk  \n    olsun x = 1\n}\n", "~eng\nif (true) {\n    let x = 1\n}\n"},
+	}
+
+	for _, tt := range tests {
+		got, err := oq_translator.Translate(tt.input, "eng")
+		if err != nil {
+			t.Fatalf("%q - Translate failed: %v", tt.input, err)
+		}
+		if got != tt.expected {
+			t.Errorf("wrong translation.\nexpected=%q\ngot=%q", tt.expected, got)
+		}
+	}
+}